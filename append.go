@@ -0,0 +1,119 @@
+package crystal
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// radix describes a fixed-width string encoding of 64-bit values.
+type radix struct {
+	alphabet string
+	// width is the number of characters per value.
+	width int
+	// bits is the number of bits per character for power-of-two alphabets,
+	// whose encodings match encoding/base32, encoding/hex and encoding/base64
+	// applied to the big-endian bytes (zero bits pad the last character). It
+	// is 0 for base36 and base62, which encode the value by repeated
+	// division.
+	bits uint
+}
+
+// Fixed-width encodings supported by appendEncoded.
+//
+//nolint:gochecknoglobals
+var (
+	hexRadix    = radix{alphabet: "0123456789abcdef", width: 16, bits: 4}
+	base32Radix = radix{alphabet: base32Alphabet, width: base32Len, bits: 5}
+	base36Radix = radix{alphabet: base36Alphabet, width: base36Len}
+	base62Radix = radix{alphabet: base62Alphabet, width: base62Len}
+	base64Radix = radix{alphabet: base64Alphabet, width: base64Len, bits: 6}
+)
+
+// base64Alphabet is the URL-safe alphabet of base64.RawURLEncoding.
+const base64Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+// base64Len is the number of base64 characters for 8 bytes without padding.
+const base64Len = 11
+
+// appendEncoded appends the r encoding of v to dst. It allocates only when dst
+// lacks the capacity for r.width more bytes.
+func appendEncoded(dst []byte, v uint64, r radix) []byte {
+	n := len(dst)
+	dst = append(dst, make([]byte, r.width)...)
+	out := dst[n:]
+
+	if r.bits == 0 {
+		base := uint64(len(r.alphabet))
+		for i := r.width - 1; i >= 0; i-- {
+			out[i] = r.alphabet[v%base]
+			v /= base
+		}
+		return dst
+	}
+
+	mask := uint64(1)<<r.bits - 1
+	pad := r.width*int(r.bits) - 64 // zero bits after the last value bit
+	for i := r.width - 1; i >= 0; i-- {
+		shift := (r.width-1-i)*int(r.bits) - pad
+		if shift < 0 {
+			out[i] = r.alphabet[(v<<uint(-shift))&mask]
+		} else {
+			out[i] = r.alphabet[(v>>uint(shift))&mask]
+		}
+	}
+	return dst
+}
+
+// AppendBase32 appends the base32 form of the ID (see Base32) to dst and
+// returns the extended slice, without allocating when dst has room.
+func (id ID) AppendBase32(dst []byte) []byte {
+	return appendEncoded(dst, id.Uint64(), base32Radix)
+}
+
+// AppendHex appends the hexadecimal form of the ID (see Hex) to dst and
+// returns the extended slice, without allocating when dst has room.
+func (id ID) AppendHex(dst []byte) []byte {
+	return appendEncoded(dst, id.Uint64(), hexRadix)
+}
+
+// AppendBase36 appends the base36 form of the ID (see Base36) to dst and
+// returns the extended slice, without allocating when dst has room.
+func (id ID) AppendBase36(dst []byte) []byte {
+	return appendEncoded(dst, id.Uint64(), base36Radix)
+}
+
+// AppendBase62 appends the base62 form of the ID (see Base62) to dst and
+// returns the extended slice, without allocating when dst has room.
+func (id ID) AppendBase62(dst []byte) []byte {
+	return appendEncoded(dst, id.Uint64(), base62Radix)
+}
+
+// AppendBase64 appends the base64 form of the ID (see Base64) to dst and
+// returns the extended slice, without allocating when dst has room.
+func (id ID) AppendBase64(dst []byte) []byte {
+	return appendEncoded(dst, id.Uint64(), base64Radix)
+}
+
+// Base64 returns the 11 character URL-safe, unpadded base64 form of the ID's
+// big-endian bytes (base64.RawURLEncoding). Its alphabet is not in ASCII
+// order, so unlike the other encodings base64 strings do not sort like IDs.
+func (id ID) Base64() string {
+	var b [base64Len]byte
+	return string(id.AppendBase64(b[:0]))
+}
+
+// ParseBase64 parses a string produced by Base64 into an ID.
+func ParseBase64(s string) (ID, error) {
+	var b [9]byte // room to detect overlong input
+	if base64.RawURLEncoding.DecodedLen(len(s)) > len(b) {
+		return 0, fmt.Errorf("invalid base64 length: %d", len(s))
+	}
+	n, err := base64.RawURLEncoding.Decode(b[:], []byte(s))
+	if err != nil {
+		return 0, err
+	}
+	if n != 8 {
+		return 0, fmt.Errorf("invalid base64 length: %d", len(s))
+	}
+	return FromBytes([8]byte(b[:8])), nil
+}
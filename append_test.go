@@ -0,0 +1,94 @@
+package crystal
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"testing"
+)
+
+func TestAppendEncodings(t *testing.T) {
+	gen := New()
+	ids := []ID{0, 1, math.MaxInt64, gen.Generate(), gen.Generate()}
+
+	for _, id := range ids {
+		var raw [8]byte
+		binary.BigEndian.PutUint64(raw[:], id.Uint64())
+
+		if got, want := id.Base32(), base32Encoding.EncodeToString(raw[:]); got != want {
+			t.Errorf("Base32(%d) = %q, want %q", id, got, want)
+		}
+		if got, want := id.Hex(), hex.EncodeToString(raw[:]); got != want {
+			t.Errorf("Hex(%d) = %q, want %q", id, got, want)
+		}
+		if got, want := id.Base64(), base64.RawURLEncoding.EncodeToString(raw[:]); got != want {
+			t.Errorf("Base64(%d) = %q, want %q", id, got, want)
+		}
+
+		prefix := []byte("id=")
+		if got := string(id.AppendBase62(prefix)); got != "id="+id.Base62() {
+			t.Errorf("AppendBase62(%d) = %q, want %q", id, got, "id="+id.Base62())
+		}
+
+		parsed, err := ParseBase64(id.Base64())
+		if err != nil || parsed != id {
+			t.Errorf("ParseBase64(%q) = %d, %v, want %d", id.Base64(), parsed, err, id)
+		}
+	}
+}
+
+func TestAppendZeroAlloc(t *testing.T) {
+	id := New().Generate()
+	buf := make([]byte, 0, 64)
+
+	appends := map[string]func([]byte) []byte{
+		"base32": id.AppendBase32,
+		"hex":    id.AppendHex,
+		"base62": id.AppendBase62,
+		"base64": id.AppendBase64,
+	}
+	for name, fn := range appends {
+		if allocs := testing.AllocsPerRun(100, func() { buf = fn(buf[:0]) }); allocs != 0 {
+			t.Errorf("Append %s allocated %.0f times, want 0", name, allocs)
+		}
+	}
+}
+
+func TestParseBase64Invalid(t *testing.T) {
+	for _, s := range []string{"", "AAAA", "AAAAAAAAAAAAAAAA", "AAAAAAAAAA!"} {
+		if _, err := ParseBase64(s); err == nil {
+			t.Errorf("ParseBase64(%q) succeeded, want error", s)
+		}
+	}
+}
+
+func benchmarkEncoding(b *testing.B, fn func(ID, []byte) []byte) {
+	id := New().Generate()
+	buf := make([]byte, 0, 32)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = fn(id, buf[:0])
+	}
+}
+
+func BenchmarkAppendBase32(b *testing.B) { benchmarkEncoding(b, ID.AppendBase32) }
+func BenchmarkAppendHex(b *testing.B)    { benchmarkEncoding(b, ID.AppendHex) }
+func BenchmarkAppendBase62(b *testing.B) { benchmarkEncoding(b, ID.AppendBase62) }
+func BenchmarkAppendBase64(b *testing.B) { benchmarkEncoding(b, ID.AppendBase64) }
+
+func BenchmarkBase32(b *testing.B) {
+	benchmarkEncoding(b, func(id ID, dst []byte) []byte { return append(dst, id.Base32()...) })
+}
+
+func BenchmarkHex(b *testing.B) {
+	benchmarkEncoding(b, func(id ID, dst []byte) []byte { return append(dst, id.Hex()...) })
+}
+
+func BenchmarkBase62(b *testing.B) {
+	benchmarkEncoding(b, func(id ID, dst []byte) []byte { return append(dst, id.Base62()...) })
+}
+
+func BenchmarkBase64(b *testing.B) {
+	benchmarkEncoding(b, func(id ID, dst []byte) []byte { return append(dst, id.Base64()...) })
+}
@@ -0,0 +1,100 @@
+package crystal
+
+import "time"
+
+// DayBounds returns the smallest and largest IDs that l can produce during the
+// UTC calendar day containing day, so that min <= id <= max selects exactly
+// the IDs from that day, as needed for day-partitioned tables. Days that
+// extend beyond the layout's time range are clamped to its first or last
+// millisecond. Bounds are meaningless for ScatteredTime layouts, whose IDs do
+// not sort by time.
+func DayBounds(day time.Time, l Layout) (min ID, max ID) {
+	y, m, d := day.UTC().Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+
+	min = l.compose(l.clampMillis(l.ticksSince(start)), 0, 0)
+	max = l.compose(l.clampMillis(l.ticksSince(end)-1), l.nodeMask(), l.stepMask())
+	max = l.withKind(max, l.kindMask())
+	return min, max
+}
+
+// clampMillis clamps a timestamp (milliseconds since l.Epoch) into the range
+// the layout's time bits can hold.
+func (l Layout) clampMillis(millis int64) int64 {
+	limit := int64(1)<<uint(l.timebits()) - 1
+	if millis < 0 {
+		return 0
+	}
+	if millis > limit {
+		return limit
+	}
+	return millis
+}
+
+// MinID returns the smallest ID l can produce: timestamp, kind, node and
+// sequence all zero (the version tag, if any, is kept). This is 0 for
+// unversioned layouts, so callers that treat 0 as "no ID" should use
+// MinID(l)+1 as a lower bound.
+func MinID(l Layout) ID {
+	return l.compose(0, 0, 0)
+}
+
+// MaxID returns the largest ID l can produce, with every timestamp, kind, node
+// and sequence bit set but the unused top bit clear, for use as an upper
+// sentinel in range scans.
+func MaxID(l Layout) ID {
+	return l.withKind(l.compose(int64(l.timeMask()), l.nodeMask(), l.stepMask()), l.kindMask()) //nolint:gosec
+}
+
+// MinIDForTime returns the smallest ID the package-level layout can produce
+// at t's millisecond (kind, node and sequence zero), clamped to the layout's
+// time range. Paired with MinIDForTime of a later instant it bounds a time
+// range scan.
+func MinIDForTime(t time.Time) ID {
+	l := DefaultLayout()
+	return l.compose(l.clampMillis(l.ticksSince(t)), 0, 0)
+}
+
+// TimeFloor returns id with its kind, node and sequence bits cleared: the
+// smallest ID l can produce in id's millisecond, for use as a per-millisecond
+// bucket key. The version tag is kept.
+func (id ID) TimeFloor(l Layout) ID {
+	return id &^ ID(uint64(1)<<l.timeShift()-1) //nolint:gosec
+}
+
+// MaxIDBefore returns the largest ID l can produce with a timestamp strictly
+// before t, so that MinIDForTime(start) <= id && id <= MaxIDBefore(end)
+// selects the half-open range [start, end). The timestamp is clamped to the
+// end of the layout's time range. For t at or before the epoch no ID
+// qualifies, and MaxIDBefore returns false.
+func MaxIDBefore(t time.Time, l Layout) (ID, bool) {
+	ticks := l.ticksSince(t) - 1
+	if ticks < 0 {
+		return 0, false
+	}
+	max := l.compose(l.clampMillis(ticks), l.nodeMask(), l.stepMask())
+	return l.withKind(max, l.kindMask()), true
+}
+
+// TruncateTime returns id with its embedded timestamp rounded down to a
+// multiple of d (as by time.Time.Truncate) and its kind, node and sequence
+// kept, e.g. to coarsen creation times to the hour in anonymized exports. The
+// result is still a valid ID and sorts roughly like the original, but the
+// rounding is lossy: IDs from the same interval now share a timestamp, and
+// may even collide if they also shared a node and step. A non-positive d
+// returns id unchanged.
+func (id ID) TruncateTime(d time.Duration, l Layout) ID {
+	if d <= 0 {
+		return id
+	}
+	ticks := l.clampMillis(l.ticksSince(l.Time(id).Truncate(d)))
+	return l.withKind(l.compose(ticks, l.Node(id), l.Step(id)), uint64(l.Kind(id)))
+}
+
+// Window returns the start of the size-aligned window holding id's timestamp
+// under l (as by time.Time.Truncate), e.g. to group IDs by minute or hour in
+// aggregation pipelines. A non-positive size returns the timestamp itself.
+func (id ID) Window(size time.Duration, l Layout) time.Time {
+	return l.Time(id).Truncate(size)
+}
@@ -0,0 +1,182 @@
+package crystal
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDayBounds(t *testing.T) {
+	noon := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	l := DefaultLayout()
+
+	min, max := DayBounds(noon, l)
+	if !l.Time(min).Equal(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("min time = %v", l.Time(min).UTC())
+	}
+	if !l.Time(max).Equal(time.Date(2024, 6, 1, 23, 59, 59, 999_000_000, time.UTC)) {
+		t.Errorf("max time = %v", l.Time(max).UTC())
+	}
+
+	for _, ts := range []time.Time{
+		noon,
+		time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 6, 1, 23, 59, 59, 999_000_000, time.UTC),
+	} {
+		id := New(WithClock(newManualClock(ts))).Generate()
+		if id < min || id > max {
+			t.Errorf("ID at %v = %d outside [%d, %d]", ts, id, min, max)
+		}
+	}
+
+	prevMin, prevMax := DayBounds(noon.AddDate(0, 0, -1), l)
+	nextMin, _ := DayBounds(noon.AddDate(0, 0, 1), l)
+	if prevMax >= min || prevMin >= prevMax {
+		t.Errorf("previous day [%d, %d] overlaps [%d, %d]", prevMin, prevMax, min, max)
+	}
+	if nextMin != max+1 {
+		t.Errorf("next day starts at %d, want %d", nextMin, max+1)
+	}
+
+	// A time zone offset does not shift the UTC day.
+	local := noon.In(time.FixedZone("UTC+11", 11*3600))
+	if lmin, lmax := DayBounds(local, l); lmin != min || lmax != max {
+		t.Errorf("DayBounds(%v) = [%d, %d], want [%d, %d]", local, lmin, lmax, min, max)
+	}
+}
+
+func TestMinMaxID(t *testing.T) {
+	l := DefaultLayout()
+	if got := MinID(l); got != 0 {
+		t.Errorf("MinID() = %d, want 0", got)
+	}
+	if got := MaxID(l); got != math.MaxInt64 {
+		t.Errorf("MaxID() = %d, want MaxInt64", got)
+	}
+
+	kinds := l
+	kinds.Timebits, kinds.Kindbits, kinds.Nodebits = 44, 3, 5
+	gen := New(WithLayout(kinds), WithKind(7, 3), WithNode(31))
+	min, max := MinID(gen.Layout()), MaxID(gen.Layout())
+	for i := 0; i < 100; i++ {
+		if id := gen.Generate(); id <= min || id > max {
+			t.Fatalf("ID %d outside [%d, %d]", id, min, max)
+		}
+	}
+	if max <= 0 {
+		t.Fatalf("MaxID() = %d, want positive", max)
+	}
+
+	versioned := l
+	versioned.Versionbits, versioned.Version = 2, 1
+	if min, max := MinID(versioned), MaxID(versioned); min != 1<<61 || max != 1<<62-1 {
+		t.Errorf("versioned bounds = [%d, %d], want [%d, %d]", min, max, int64(1)<<61, int64(1)<<62-1)
+	}
+}
+
+func TestTimeFloor(t *testing.T) {
+	origNodebits := Nodebits
+	t.Cleanup(func() {
+		Nodebits = origNodebits
+	})
+	Nodebits = 4
+
+	ts := time.Date(2024, 6, 1, 12, 0, 0, 987_000_000, time.UTC)
+	gen := New(WithClock(newManualClock(ts)), WithNode(9))
+	id := gen.Generate()
+
+	floor := id.TimeFloor(gen.Layout())
+	if floor != MinIDForTime(id.Time()) {
+		t.Fatalf("TimeFloor() = %d, want MinIDForTime() = %d", floor, MinIDForTime(id.Time()))
+	}
+	if floor.Node() != 0 || gen.Layout().Step(floor) != 0 || !floor.Time().Equal(ts) {
+		t.Fatalf("TimeFloor() = node %d, step %d, time %v", floor.Node(), gen.Layout().Step(floor), floor.Time())
+	}
+	if floor > id {
+		t.Fatalf("TimeFloor() = %d exceeds %d", floor, id)
+	}
+}
+
+func TestMaxIDBefore(t *testing.T) {
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	l := DefaultLayout()
+
+	exact := New(WithClock(newManualClock(at))).Generate()
+	earlier := New(WithClock(newManualClock(at.Add(-time.Millisecond)))).Generate()
+
+	before, ok := MaxIDBefore(at, l)
+	if !ok {
+		t.Fatalf("MaxIDBefore(%v) found no ID", at)
+	}
+	if exact <= before {
+		t.Fatalf("ID at t (%d) within < t range (max %d)", exact, before)
+	}
+	if earlier > before {
+		t.Fatalf("ID before t (%d) outside < t range (max %d)", earlier, before)
+	}
+	if before+1 != MinIDForTime(at) {
+		t.Fatalf("MaxIDBefore()+1 = %d, want MinIDForTime() = %d", before+1, MinIDForTime(at))
+	}
+
+	epoch := time.UnixMilli(l.Epoch)
+	for _, ts := range []time.Time{epoch, epoch.Add(-time.Hour)} {
+		if id, ok := MaxIDBefore(ts, l); ok {
+			t.Errorf("MaxIDBefore(%v) = %d, true; want no ID before the epoch", ts.UTC(), id)
+		}
+	}
+	if id, ok := MaxIDBefore(epoch.Add(time.Millisecond), l); !ok || l.millis(id) != 0 {
+		t.Errorf("MaxIDBefore(epoch+1ms) = %d, %v; want an ID in the epoch's first millisecond", id, ok)
+	}
+}
+
+func TestTruncateTime(t *testing.T) {
+	at := time.Date(2024, 6, 1, 12, 34, 56, 789_000_000, time.UTC)
+	gen := New(WithClock(newManualClock(at)))
+	l := gen.Layout()
+	id := gen.Generate()
+
+	hour := id.TruncateTime(time.Hour, l)
+	if got, want := hour.Time(), at.Truncate(time.Hour); !got.Equal(want) {
+		t.Fatalf("TruncateTime(hour).Time() = %v, want %v", got, want)
+	}
+	if l.Step(hour) != l.Step(id) || hour > id {
+		t.Fatalf("TruncateTime(hour) = %d (step %d), want step %d and at most %d", hour, l.Step(hour), l.Step(id), id)
+	}
+	if got := id.TruncateTime(0, l); got != id {
+		t.Fatalf("TruncateTime(0) = %d, want %d", got, id)
+	}
+	second := id.TruncateTime(time.Second, l)
+	if got, want := second.Time(), at.Truncate(time.Second); !got.Equal(want) {
+		t.Fatalf("TruncateTime(second).Time() = %v, want %v", got, want)
+	}
+}
+
+func TestWindow(t *testing.T) {
+	minute := time.Date(2024, 6, 1, 12, 34, 0, 0, time.UTC)
+	clock := newManualClock(minute)
+	gen := New(WithClock(clock))
+	l := gen.Layout()
+
+	var ids []ID
+	for _, d := range []time.Duration{0, 1, 30 * time.Second, time.Minute - time.Millisecond} {
+		clock.Set(minute.Add(d))
+		ids = append(ids, gen.Generate())
+	}
+	for _, id := range ids {
+		if got := id.Window(time.Minute, l); !got.Equal(minute) {
+			t.Errorf("Window(minute) of ID at %v = %v, want %v", l.Time(id), got, minute)
+		}
+	}
+
+	clock.Set(minute.Add(time.Minute))
+	next := gen.Generate()
+	if got := next.Window(time.Minute, l); !got.Equal(minute.Add(time.Minute)) {
+		t.Errorf("Window(minute) of the next minute = %v, want %v", got, minute.Add(time.Minute))
+	}
+	if got := next.Window(time.Hour, l); !got.Equal(minute.Truncate(time.Hour)) {
+		t.Errorf("Window(hour) = %v, want %v", got, minute.Truncate(time.Hour))
+	}
+	if got := ids[2].Window(0, l); !got.Equal(l.Time(ids[2])) {
+		t.Errorf("Window(0) = %v, want %v", got, l.Time(ids[2]))
+	}
+}
@@ -0,0 +1,89 @@
+package crystal
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// WithByteOrder sets the byte order the generator's Bytes, Base32 and Hex
+// methods (and GenerateString and GenerateHex) serialize IDs in, and its
+// ParseBase32 and ParseHex methods read them back with, for peers that decode
+// IDs as little-endian. The default is binary.BigEndian, which matches the
+// package-level ID methods and keeps encodings sorting like the IDs.
+//
+// The order is not recorded in the encoding: a string written under one order
+// and read under the other decodes to a different ID without any error, so
+// producers and consumers must agree on it.
+func WithByteOrder(order binary.ByteOrder) Option {
+	return func(g *Generator) error {
+		if order == nil {
+			return fmt.Errorf("byte order must not be nil")
+		}
+		g.byteOrder = order
+		return nil
+	}
+}
+
+// Bytes returns the 8 bytes of id in the generator's byte order.
+func (g *Generator) Bytes(id ID) []byte {
+	b := make([]byte, 8)
+	g.order().PutUint64(b, id.Uint64())
+	return b
+}
+
+// Base32 returns the base32 form of id's bytes in the generator's byte order.
+func (g *Generator) Base32(id ID) string {
+	var b [base32Len]byte
+	return string(appendEncoded(b[:0], g.ordered(id), base32Radix))
+}
+
+// Hex returns the hexadecimal form of id's bytes in the generator's byte
+// order.
+func (g *Generator) Hex(id ID) string {
+	var b [16]byte
+	return string(appendEncoded(b[:0], g.ordered(id), hexRadix))
+}
+
+// ParseBase32 parses a string produced by the generator's Base32 method.
+func (g *Generator) ParseBase32(s string) (ID, error) {
+	raw, err := ParseBase32(s)
+	if err != nil {
+		return 0, err
+	}
+	return g.fromOrdered(raw)
+}
+
+// ParseHex parses a string produced by the generator's Hex method.
+func (g *Generator) ParseHex(s string) (ID, error) {
+	raw, err := ParseHex(s)
+	if err != nil {
+		return 0, err
+	}
+	return g.fromOrdered(raw)
+}
+
+// order returns the generator's byte order, big-endian by default.
+func (g *Generator) order() binary.ByteOrder {
+	if g.byteOrder == nil {
+		return binary.BigEndian
+	}
+	return g.byteOrder
+}
+
+// ordered returns the value whose big-endian bytes are id's bytes in the
+// generator's byte order, ready for the big-endian encoders.
+func (g *Generator) ordered(id ID) uint64 {
+	var b [8]byte
+	g.order().PutUint64(b[:], id.Uint64())
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// fromOrdered undoes ordered for a value decoded by a big-endian parser.
+func (g *Generator) fromOrdered(raw ID) (ID, error) {
+	b := raw.Array()
+	v := g.order().Uint64(b[:])
+	if !g.layout.FullWidth && v>>totalBits != 0 {
+		return 0, fmt.Errorf("%w: decoded value %#x exceeds 63 bits", ErrOutOfRange, v)
+	}
+	return ID(v), nil //nolint:gosec
+}
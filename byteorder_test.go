@@ -0,0 +1,61 @@
+package crystal
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestWithByteOrder(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+		gen := New(WithByteOrder(order))
+		id := gen.Generate()
+
+		b := gen.Bytes(id)
+		if got := order.Uint64(b); got != id.Uint64() {
+			t.Errorf("%s: Bytes() decodes to %d, want %d", order, got, id)
+		}
+
+		parsed, err := gen.ParseBase32(gen.Base32(id))
+		if err != nil || parsed != id {
+			t.Errorf("%s: ParseBase32(Base32()) = %d, %v, want %d", order, parsed, err, id)
+		}
+		parsed, err = gen.ParseHex(gen.Hex(id))
+		if err != nil || parsed != id {
+			t.Errorf("%s: ParseHex(Hex()) = %d, %v, want %d", order, parsed, err, id)
+		}
+		if _, err := gen.ParseBase32(gen.GenerateString()); err != nil {
+			t.Errorf("%s: ParseBase32(GenerateString()) failed: %v", order, err)
+		}
+		formID, s, _ := gen.GenerateWithForms()
+		if s != gen.Base32(formID) {
+			t.Errorf("%s: GenerateWithForms() string = %q, want %q", order, s, gen.Base32(formID))
+		}
+	}
+}
+
+func TestWithByteOrderDefault(t *testing.T) {
+	gen := New()
+	id := gen.Generate()
+	if gen.Base32(id) != id.Base32() || gen.Hex(id) != id.Hex() {
+		t.Fatal("default byte order does not match the ID encoders")
+	}
+
+	le := New(WithByteOrder(binary.LittleEndian))
+	if le.Hex(id) == id.Hex() {
+		t.Fatal("little-endian Hex() matches big-endian")
+	}
+}
+
+func TestRequestIDByteOrder(t *testing.T) {
+	gen := New(WithByteOrder(binary.LittleEndian))
+	prev := gen.Generate()
+
+	rid := gen.RequestID()
+	if len(rid) != base32Len {
+		t.Fatalf("RequestID() = %q, want %d characters", rid, base32Len)
+	}
+	id, err := gen.ParseBase32(rid)
+	if err != nil || id <= prev {
+		t.Fatalf("ParseBase32(RequestID()) = %d, %v; want an ID after %d", id, err, prev)
+	}
+}
@@ -0,0 +1,31 @@
+package crystal
+
+// GenerateChild creates an ID that shares parent's timestamp field, so that
+// range scans over the parent's time also return its children. The child
+// carries the generator's node and a step from a sequence dedicated to
+// children.
+//
+// That sequence is shared by all parents and wraps after 2^stepBits
+// children (2,097,152 with the default layout): children of one parent are
+// distinct as long as the generator issues no more than that many children
+// between the first and the last of them. Children live in their parent's
+// millisecond and are not coordinated with the regular sequence, so use them
+// only in keyspaces scoped to the parent (e.g. (parent, child) pairs), where
+// they cannot meet unrelated IDs from the same millisecond.
+func (g *Generator) GenerateChild(parent ID) ID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		panic(ErrClosed)
+	}
+
+	l := g.layout
+	if !g.childInit {
+		g.childStep = g.newCounter()
+		g.childInit = true
+	}
+	g.childStep = (g.childStep + 1) & l.stepMask()
+
+	return l.withKind(l.compose(l.millis(parent), g.node, g.childStep), g.kind)
+}
@@ -0,0 +1,32 @@
+package crystal
+
+import "testing"
+
+func TestGenerateChild(t *testing.T) {
+	gen := New()
+	parent := gen.Generate()
+	l := gen.Layout()
+
+	seen := make(map[uint64]bool)
+	for i := 0; i < 1000; i++ {
+		child := gen.GenerateChild(parent)
+
+		if child.Uint64()>>l.timeShift() != parent.Uint64()>>l.timeShift() {
+			t.Fatalf("child %d does not share parent %d time prefix", child, parent)
+		}
+		if !l.Time(child).Equal(l.Time(parent)) {
+			t.Fatalf("child time %v, want %v", l.Time(child), l.Time(parent))
+		}
+
+		step := l.Step(child)
+		if seen[step] {
+			t.Fatalf("duplicate child step %d", step)
+		}
+		seen[step] = true
+
+		// Generating other IDs in between does not disturb the children.
+		if i%100 == 0 {
+			gen.Generate()
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package crystal
+
+import "time"
+
+// Clock supplies the current time to a Generator.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the default Clock, backed by time.Now.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
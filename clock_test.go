@@ -0,0 +1,136 @@
+package crystal
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// manualClock is a Clock whose time only changes when the test says so.
+type manualClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func newManualClock(t time.Time) *manualClock {
+	return &manualClock{t: t}
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *manualClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = t
+}
+
+func (c *manualClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func TestWithClock(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newManualClock(start)
+
+	gen := New(WithClock(clock))
+
+	id := gen.Generate()
+	if !id.Time().Equal(start) {
+		t.Fatalf("expected ID time %v, got %v", start, id.Time())
+	}
+
+	clock.Add(5 * time.Millisecond)
+	id = gen.Generate()
+	if !id.Time().Equal(start.Add(5 * time.Millisecond)) {
+		t.Fatalf("expected ID time %v, got %v", start.Add(5*time.Millisecond), id.Time())
+	}
+
+	if _, err := NewGenerator(WithClock(nil)); err == nil {
+		t.Fatal("expected error for nil clock")
+	}
+}
+
+func TestBackwardClockMonotonic(t *testing.T) {
+	origTimebits, origNodebits := Timebits, Nodebits
+	t.Cleanup(func() {
+		Timebits, Nodebits = origTimebits, origNodebits
+	})
+	Timebits, Nodebits = maxTimebits, 9 // 6 step bits, so rollovers happen often
+
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newManualClock(start)
+	gen := New(WithClock(clock))
+	l := gen.Layout()
+
+	prev := gen.Generate()
+	next := func(phase string) {
+		t.Helper()
+		id := gen.Generate()
+		if id <= prev {
+			t.Fatalf("%s: ID %d after %d, want strictly increasing", phase, id, prev)
+		}
+		prev = id
+	}
+
+	// While the clock stays 50ms behind, many rollovers borrow milliseconds
+	// ahead of the last one issued.
+	clock.Add(-50 * time.Millisecond)
+	for i := 0; i < 1000; i++ {
+		next("behind")
+	}
+	if lead := l.Time(prev).Sub(start); lead <= 0 {
+		t.Fatalf("IDs did not borrow ahead of the clock: lead %v", lead)
+	}
+
+	// Let the clock catch up and overtake the borrowed milliseconds. Skip the
+	// tick where it meets the last ID's millisecond, whose rollover would
+	// wait for the manual clock; past it, issue fewer IDs per tick than a
+	// fresh millisecond guarantees.
+	for tick := 0; tick < 200; tick++ {
+		clock.Add(time.Millisecond)
+		if clock.Now().Equal(l.Time(prev)) {
+			continue
+		}
+		for i := 0; i < 10; i++ {
+			next("catching up")
+		}
+	}
+	if !l.Time(prev).Equal(clock.Now()) {
+		t.Fatalf("last ID at %v, want the clock's %v once caught up", l.Time(prev), clock.Now())
+	}
+}
+
+func TestMaxBackwardDrift(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newManualClock(start)
+
+	gen := New(WithClock(clock))
+	first := gen.Generate()
+
+	if d := gen.MaxBackwardDrift(); d != 0 {
+		t.Fatalf("expected no drift, got %v", d)
+	}
+
+	clock.Add(-250 * time.Millisecond)
+	second := gen.Generate()
+	if second <= first {
+		t.Fatalf("IDs not in order across backward jump: %d <= %d", second, first)
+	}
+
+	if d := gen.MaxBackwardDrift(); d != 250*time.Millisecond {
+		t.Fatalf("expected drift 250ms, got %v", d)
+	}
+
+	// A smaller jump must not lower the recorded maximum.
+	clock.Set(start.Add(-100 * time.Millisecond))
+	_ = gen.Generate()
+	if d := gen.MaxBackwardDrift(); d != 250*time.Millisecond {
+		t.Fatalf("expected drift to stay 250ms, got %v", d)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kwo/crystal"
+)
+
+// parsers read an ID in each format accepted by -from.
+//
+//nolint:gochecknoglobals
+var parsers = map[string]func(string) (crystal.ID, error){
+	"auto":    crystal.ParseAny,
+	"base32":  crystal.ParseBase32,
+	"base62":  crystal.ParseBase62,
+	"base64":  crystal.ParseBase64,
+	"hex":     crystal.ParseHex,
+	"decimal": parseDecimal,
+}
+
+// encoders write an ID in each format accepted by -to.
+//
+//nolint:gochecknoglobals
+var encoders = map[string]func(crystal.ID) string{
+	"base32":  crystal.ID.Base32,
+	"base62":  crystal.ID.Base62,
+	"base64":  crystal.ID.Base64,
+	"hex":     crystal.ID.Hex,
+	"decimal": func(id crystal.ID) string { return strconv.FormatInt(id.Int64(), 10) },
+}
+
+// runConvert implements the convert subcommand: it reads one ID per line
+// from in, writes each in the -to format to out and reports unparseable
+// lines to errOut. It returns the process exit code: 0 on success, 1 if any
+// line failed and 2 for usage errors.
+func runConvert(args []string, in io.Reader, out, errOut io.Writer) int {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	from := fs.String("from", "auto", "input format: "+formatNames(parsers))
+	to := fs.String("to", "decimal", "output format: "+formatNames(encoders))
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	parse, ok := parsers[*from]
+	if !ok {
+		fmt.Fprintf(errOut, "convert: unknown -from format %q\n", *from)
+		return 2
+	}
+	encode, ok := encoders[*to]
+	if !ok {
+		fmt.Fprintf(errOut, "convert: unknown -to format %q\n", *to)
+		return 2
+	}
+
+	w := bufio.NewWriter(out)
+	defer w.Flush()
+
+	status := 0
+	scanner := bufio.NewScanner(in)
+	for line := 1; scanner.Scan(); line++ {
+		s := strings.TrimSpace(scanner.Text())
+		if s == "" {
+			continue
+		}
+		id, err := parse(s)
+		if err != nil {
+			fmt.Fprintf(errOut, "line %d: %q: %v\n", line, s, err)
+			status = 1
+			continue
+		}
+		fmt.Fprintln(w, encode(id))
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(errOut, "convert: %v\n", err)
+		return 1
+	}
+	return status
+}
+
+// parseDecimal parses a non-negative decimal int64 ID.
+func parseDecimal(s string) (crystal.ID, error) {
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return crystal.Parse(i)
+}
+
+// formatNames lists the keys of m, sorted and comma-separated.
+func formatNames[V any](m map[string]V) string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kwo/crystal"
+)
+
+func TestRunConvert(t *testing.T) {
+	id := crystal.ID(237755712226918401)
+
+	tests := []struct {
+		name    string
+		args    []string
+		in      string
+		want    string
+		wantErr []string
+		code    int
+	}{
+		{
+			name: "base32 to hex",
+			args: []string{"-from", "base32", "-to", "hex"},
+			in:   id.Base32() + "\n\n" + id.Base32() + "\n",
+			want: id.Hex() + "\n" + id.Hex() + "\n",
+		},
+		{
+			name: "auto to decimal",
+			args: nil,
+			in:   id.Hex() + "\n" + id.Base62() + "\n",
+			want: "237755712226918401\n237755712226918401\n",
+		},
+		{
+			name:    "bad line",
+			args:    []string{"-from", "hex", "-to", "base32"},
+			in:      id.Hex() + "\nnot-an-id\n" + id.Hex() + "\n",
+			want:    id.Base32() + "\n" + id.Base32() + "\n",
+			wantErr: []string{"line 2:", `"not-an-id"`},
+			code:    1,
+		},
+		{
+			name:    "unknown from",
+			args:    []string{"-from", "base99"},
+			wantErr: []string{`unknown -from format "base99"`},
+			code:    2,
+		},
+		{
+			name:    "unknown to",
+			args:    []string{"-to", "roman"},
+			wantErr: []string{`unknown -to format "roman"`},
+			code:    2,
+		},
+		{
+			name: "bad flag",
+			args: []string{"-verbose"},
+			code: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			code := runConvert(tt.args, strings.NewReader(tt.in), &out, &errOut)
+			if code != tt.code {
+				t.Fatalf("exit code = %d, want %d (stderr %q)", code, tt.code, errOut.String())
+			}
+			if out.String() != tt.want {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.want)
+			}
+			for _, s := range tt.wantErr {
+				if !strings.Contains(errOut.String(), s) {
+					t.Errorf("stderr = %q, want it to contain %q", errOut.String(), s)
+				}
+			}
+			if tt.code == 0 && errOut.Len() != 0 {
+				t.Errorf("stderr = %q, want empty", errOut.String())
+			}
+		})
+	}
+}
@@ -11,8 +11,21 @@ import (
 )
 
 func main() {
+	// Subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "convert":
+			os.Exit(runConvert(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+		case "info":
+			fmt.Print(crystal.DefaultLayout().Report())
+			return
+		}
+	}
+
 	// Create a new generator
 	crystal.Epoch = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	// Show decoded times in the local time zone
+	crystal.TimeLocation = time.Local
 	gen := crystal.New()
 
 	fmt.Printf("Generator initialized:\n")
@@ -0,0 +1,94 @@
+package crystal
+
+import (
+	"cmp"
+	"crypto/subtle"
+	"encoding/binary"
+	"time"
+)
+
+// Less compares two IDs for use with slices.SortFunc, returning a negative
+// number when a < b, zero when a == b and a positive number when a > b. IDs
+// are time-ordered, so sorting with Less yields chronological order.
+func Less(a, b ID) int {
+	return cmp.Compare(a, b)
+}
+
+// Greater is the reverse of Less and sorts IDs newest first.
+func Greater(a, b ID) int {
+	return cmp.Compare(b, a)
+}
+
+// EqualConstantTime reports whether id and other are equal, taking the same
+// time regardless of where they differ. Use it instead of == when IDs double
+// as capability tokens (e.g. unguessable links) and an attacker could probe
+// them through response timing; for ordinary lookups == is fine.
+func (id ID) EqualConstantTime(other ID) bool {
+	var a, b [8]byte
+	binary.BigEndian.PutUint64(a[:], id.Uint64())
+	binary.BigEndian.PutUint64(b[:], other.Uint64())
+	return subtle.ConstantTimeCompare(a[:], b[:]) == 1
+}
+
+// SameMillis reports whether id and other carry the same timestamp under the
+// package-level layout, ignoring their node and sequence bits.
+func (id ID) SameMillis(other ID) bool {
+	shift := currentTimeShift()
+	return id.Uint64()>>shift == other.Uint64()>>shift
+}
+
+// Delta returns how far b lies from a under layout l: the difference between
+// their timestamps and between their sequence steps, both positive when b is
+// the later ID. A step difference is only meaningful for IDs from the same
+// millisecond and generator, since each millisecond starts at a random step.
+func Delta(a, b ID, l Layout) (dt time.Duration, dStep int64) {
+	dt = time.Duration(l.millis(b)-l.millis(a)) * l.tick()
+	dStep = int64(l.Step(b)) - int64(l.Step(a)) //nolint:gosec
+	return dt, dStep
+}
+
+// IsSorted reports whether ids is in strictly ascending order, as IDs read
+// back from an append-only log should be. Duplicates count as unsorted.
+func IsSorted(ids []ID) bool {
+	return FirstUnsorted(ids) < 0
+}
+
+// FirstUnsorted returns the index of the first ID in ids that is not greater
+// than its predecessor, or -1 if ids is strictly ascending.
+func FirstUnsorted(ids []ID) int {
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			return i
+		}
+	}
+	return -1
+}
+
+// OrderConfidence reports how far the numeric order of a and b under layout
+// l can be trusted as their creation order. IDs from different milliseconds
+// are definitely ordered and yield 1. IDs from the same millisecond yield
+// 0.5: each generator starts every millisecond at a random step, so unless
+// both came from the same generator (whose steps do increase) their order
+// within the millisecond says nothing about which was created first.
+func (id ID) OrderConfidence(b ID, l Layout) float64 {
+	if l.millis(id) != l.millis(b) {
+		return 1
+	}
+	return 0.5
+}
+
+// Sub returns the numeric distance id - other, e.g. the gap between the last
+// ID seen and a target in keyset pagination. Both IDs are non-negative 63-bit
+// values, so the result cannot overflow; it is negative when other is the
+// larger ID.
+func (id ID) Sub(other ID) int64 {
+	return id.Int64() - other.Int64()
+}
+
+// Add returns id moved by delta, for cursor arithmetic: id.Add(other.Sub(id))
+// == other. The addition wraps like int64 arithmetic, so a delta that leaves
+// the range [0, math.MaxInt64] yields a negative (invalid) ID rather than an
+// error; callers moving cursors by untrusted amounts should check the result.
+func (id ID) Add(delta int64) ID {
+	return ID(id.Int64() + delta)
+}
@@ -0,0 +1,172 @@
+package crystal
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLessGreater(t *testing.T) {
+	gen := New()
+
+	ids := make([]ID, 500)
+	for i := range ids {
+		ids[i] = gen.Generate()
+	}
+
+	shuffled := slices.Clone(ids)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	slices.SortFunc(shuffled, Less)
+	if !slices.Equal(shuffled, ids) {
+		t.Fatal("SortFunc(Less) did not restore generation order")
+	}
+
+	slices.SortFunc(shuffled, Greater)
+	for i := range shuffled {
+		if shuffled[i] != ids[len(ids)-1-i] {
+			t.Fatal("SortFunc(Greater) did not produce reverse generation order")
+		}
+	}
+
+	if Less(ids[0], ids[0]) != 0 || Greater(ids[0], ids[0]) != 0 {
+		t.Error("comparing an ID with itself should return 0")
+	}
+}
+
+func TestEqualConstantTime(t *testing.T) {
+	gen := New()
+
+	ids := make([]ID, 100)
+	for i := range ids {
+		ids[i] = gen.Generate()
+	}
+
+	for _, a := range ids {
+		for _, b := range ids {
+			if got, want := a.EqualConstantTime(b), a == b; got != want {
+				t.Fatalf("EqualConstantTime(%d, %d) = %v, want %v", a, b, got, want)
+			}
+		}
+	}
+}
+
+func TestSameMillis(t *testing.T) {
+	clock := newManualClock(time.Now())
+	gen := New(WithClock(clock))
+
+	a := gen.Generate()
+	b := gen.Generate()
+	if !a.SameMillis(b) {
+		t.Fatalf("IDs from the same millisecond reported different: %d, %d", a, b)
+	}
+
+	clock.Add(time.Millisecond)
+	c := gen.Generate()
+	if a.SameMillis(c) {
+		t.Fatalf("IDs a millisecond apart reported the same: %d, %d", a, c)
+	}
+}
+
+func TestDelta(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newManualClock(start)
+	gen := New(WithClock(clock))
+
+	a := gen.Generate()
+	a2 := gen.Generate()
+	clock.Add(1500 * time.Millisecond)
+	b := gen.Generate()
+
+	l := gen.Layout()
+	if dt, _ := Delta(a, b, l); dt != 1500*time.Millisecond {
+		t.Errorf("Delta(a, b) dt = %s, want 1.5s", dt)
+	}
+	if dt, _ := Delta(b, a, l); dt != -1500*time.Millisecond {
+		t.Errorf("Delta(b, a) dt = %s, want -1.5s", dt)
+	}
+	if dt, dStep := Delta(a, a2, l); dt != 0 || dStep != 1 {
+		t.Errorf("Delta(a, a2) = %s, %d, want 0s, 1", dt, dStep)
+	}
+}
+
+func TestFirstUnsorted(t *testing.T) {
+	gen := New()
+	ids := make([]ID, 100)
+	for i := range ids {
+		ids[i] = gen.Generate()
+	}
+
+	if !IsSorted(ids) || FirstUnsorted(ids) != -1 {
+		t.Fatal("generated IDs reported unsorted")
+	}
+	if !IsSorted(nil) || !IsSorted(ids[:1]) {
+		t.Fatal("empty or single-element slice reported unsorted")
+	}
+
+	swapped := slices.Clone(ids)
+	swapped[40], swapped[41] = swapped[41], swapped[40]
+	if IsSorted(swapped) || FirstUnsorted(swapped) != 41 {
+		t.Fatalf("FirstUnsorted(swapped) = %d, want 41", FirstUnsorted(swapped))
+	}
+
+	dup := slices.Clone(ids)
+	dup[70] = dup[69]
+	if got := FirstUnsorted(dup); got != 70 {
+		t.Fatalf("FirstUnsorted(duplicate) = %d, want 70", got)
+	}
+}
+
+func TestOrderConfidence(t *testing.T) {
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newManualClock(at)
+	gen := New(WithClock(clock))
+	l := gen.Layout()
+
+	a := gen.Generate()
+	other := New(WithClock(newManualClock(at))).Generate()
+	clock.Add(time.Millisecond)
+	b := gen.Generate()
+
+	if c := a.OrderConfidence(b, l); c != 1 {
+		t.Errorf("different milliseconds: OrderConfidence() = %v, want 1", c)
+	}
+	if c := b.OrderConfidence(a, l); c != 1 {
+		t.Errorf("different milliseconds, reversed: OrderConfidence() = %v, want 1", c)
+	}
+	if c := a.OrderConfidence(other, l); c >= 1 {
+		t.Errorf("same millisecond: OrderConfidence() = %v, want less than 1", c)
+	}
+}
+
+func TestSubAdd(t *testing.T) {
+	gen := New()
+	a := gen.Generate()
+	b := gen.Generate()
+
+	if d := b.Sub(a); d <= 0 {
+		t.Fatalf("later.Sub(earlier) = %d, want positive", d)
+	}
+	if d := a.Sub(b); d != -b.Sub(a) {
+		t.Fatalf("earlier.Sub(later) = %d, want %d", d, -b.Sub(a))
+	}
+	if got := a.Add(b.Sub(a)); got != b {
+		t.Fatalf("a.Add(b.Sub(a)) = %d, want %d", got, b)
+	}
+	if got := b.Add(a.Sub(b)); got != a {
+		t.Fatalf("b.Add(a.Sub(b)) = %d, want %d", got, a)
+	}
+	if got := ID(100).Add(-42); got != 58 {
+		t.Fatalf("ID(100).Add(-42) = %d, want 58", got)
+	}
+	if got := ID(0).Sub(math.MaxInt64); got != -math.MaxInt64 {
+		t.Fatalf("ID(0).Sub(max) = %d, want %d", got, -math.MaxInt64)
+	}
+	if got := ID(math.MaxInt64).Add(1); got.Int64() >= 0 {
+		t.Fatalf("ID(max).Add(1) = %d, want a wrapped negative value", got)
+	}
+}
@@ -0,0 +1,56 @@
+package crystal
+
+import "time"
+
+// LayoutsCompatible reports whether a and b interpret every value the same
+// way: same epoch, version tag, widths (after clamping) and encoding flags.
+// Only then can IDs stored under one layout be mixed freely with IDs issued
+// under the other.
+//
+// Layouts that differ are never compatible, because the same value then
+// stands for different times: changing Timebits moves the timestamp field, so
+// a value issued under the new layout decodes to an unrelated time under the
+// old one and may equal a value issued under it. Use SafeCutover to check
+// whether switching layouts at a given moment at least keeps new IDs above
+// all old ones.
+func LayoutsCompatible(a, b Layout) bool {
+	return a.Epoch == b.Epoch &&
+		a.versionbits() == b.versionbits() &&
+		uint64(a.Version)&a.versionMask() == uint64(b.Version)&b.versionMask() &&
+		a.timebits() == b.timebits() &&
+		a.kindbits() == b.kindbits() &&
+		a.nodebits() == b.nodebits() &&
+		a.workerbits() == b.workerbits() &&
+		a.FullWidth == b.FullWidth &&
+		a.ScatteredTime == b.ScatteredTime &&
+		a.Precision == b.Precision
+}
+
+// SafeCutover reports whether switching from layout a to layout b at the
+// given moment is free of aliasing: every ID b can issue from then on is
+// greater than every ID a can have issued before, so stored and new IDs never
+// collide and still sort in issue order. Old IDs must still be decoded with a.
+// Each layout's timestamp is taken in its own precision, and IDs a issued
+// earlier in the unit holding at count as issued before.
+//
+// Widening Timebits (e.g. 42 to 44) with the same epoch shifts the timestamp
+// field down, so new IDs are numerically smaller than old ones and the
+// cutover is not safe; narrowing it, or moving the epoch back, makes new IDs
+// larger and typically is. Scattered layouts never cut over safely.
+func SafeCutover(a, b Layout, at time.Time) bool {
+	if a.ScatteredTime || b.ScatteredTime {
+		return false
+	}
+
+	// a's last ID falls in the unit holding the instant just before at, and
+	// b's first in the unit holding at, each in its own layout's precision.
+	lastA := a.compose(a.clampMillis(a.ticksSince(at.Add(-time.Nanosecond))), a.nodeMask(), a.stepMask())
+	lastA = a.withKind(lastA, a.kindMask())
+
+	firstTicks := b.ticksSince(at)
+	if firstTicks < 0 || firstTicks != b.clampMillis(firstTicks) {
+		return false
+	}
+	firstB := b.compose(firstTicks, 0, 0)
+	return firstB.Uint64() > lastA.Uint64()
+}
@@ -0,0 +1,96 @@
+package crystal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLayoutsCompatible(t *testing.T) {
+	l42 := DefaultLayout()
+	l42.Timebits = 42
+	l44 := l42
+	l44.Timebits = 44
+
+	if !LayoutsCompatible(l42, l42) {
+		t.Error("a layout must be compatible with itself")
+	}
+
+	// Out-of-range values clamp to the same effective layout.
+	l48, l50 := l42, l42
+	l48.Timebits, l50.Timebits = 48, 50
+	if !LayoutsCompatible(l48, l50) {
+		t.Error("layouts that clamp to the same widths should be compatible")
+	}
+
+	// Widening Timebits from 42 to 44 moves the timestamp field: the same
+	// value decodes to different times, so the layouts are not compatible.
+	if LayoutsCompatible(l42, l44) {
+		t.Error("42- and 44-bit layouts must not be compatible")
+	}
+
+	// Switching to 44 bits makes new IDs numerically smaller than existing
+	// 42-bit ones, so they could alias stored IDs.
+	at := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	if SafeCutover(l42, l44, at) {
+		t.Error("cutover from 42 to 44 time bits should not be safe")
+	}
+	oldID := l42.compose(at.UnixMilli()-1-l42.Epoch, 0, 0)
+	newID := l44.compose(at.UnixMilli()-l44.Epoch, 0, 0)
+	if newID > oldID {
+		t.Fatalf("expected 44-bit ID %d below 42-bit ID %d", newID, oldID)
+	}
+
+	// Narrowing from 44 to 42 bits keeps new IDs above old ones.
+	if !SafeCutover(l44, l42, at) {
+		t.Error("cutover from 44 to 42 time bits should be safe")
+	}
+	if !SafeCutover(l42, l42, at) {
+		t.Error("cutover to the same layout should be safe")
+	}
+}
+
+func TestSafeCutoverPrecision(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	millis := Layout{Timebits: 42}
+	seconds := Layout{Timebits: 32, Precision: Second}
+	micros := Layout{Epoch: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC).UnixMilli(), Timebits: 48, Precision: Microsecond}
+
+	tests := []struct {
+		name string
+		a, b Layout
+		safe bool
+	}{
+		// 397177100697599999 before, 406709351114342400 after.
+		{"millisecond to second", millis, seconds, true},
+		{"second to millisecond", seconds, millis, false},
+		{"microsecond to millisecond", micros, millis, true},
+		{"millisecond to microsecond", millis, micros, false},
+	}
+	for _, tt := range tests {
+		if got := SafeCutover(tt.a, tt.b, at); got != tt.safe {
+			t.Errorf("%s: SafeCutover() = %v, want %v", tt.name, got, tt.safe)
+		}
+	}
+
+	// Mid-second, IDs issued earlier in the same second may sort above the
+	// first new one.
+	if SafeCutover(seconds, seconds, at.Add(500*time.Millisecond)) {
+		t.Error("mid-second cutover at second precision should not be safe")
+	}
+}
+
+func TestLayoutsCompatibleVersion(t *testing.T) {
+	plain := Layout{Timebits: 42}
+	versioned := Layout{Timebits: 42, Versionbits: 1, Version: 1}
+	if LayoutsCompatible(plain, versioned) || LayoutsCompatible(versioned, plain) {
+		t.Error("layouts with and without version bits must not be compatible")
+	}
+	other := versioned
+	other.Version = 0
+	if LayoutsCompatible(versioned, other) {
+		t.Error("layouts with different versions must not be compatible")
+	}
+	if !LayoutsCompatible(versioned, versioned) {
+		t.Error("a versioned layout must be compatible with itself")
+	}
+}
@@ -0,0 +1,61 @@
+package crystal
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrAlreadyConfigured is returned by ConfigureOnce when the package was
+// already configured with a different Config.
+var ErrAlreadyConfigured = errors.New("crystal: package already configured")
+
+// Config holds the package-level layout settings applied by ConfigureOnce.
+// Each field sets the package variable of the same name.
+type Config struct {
+	Epoch         int64
+	Timebits      int
+	Versionbits   int
+	LayoutVersion uint8
+	Kindbits      int
+	Nodebits      int
+	Workerbits    int
+}
+
+// configured records the Config applied by the first ConfigureOnce call.
+//
+//nolint:gochecknoglobals
+var (
+	configMu   sync.Mutex
+	configured *Config
+)
+
+// ConfigureOnce sets the package-level layout variables from cfg, but only
+// the first time it is called: the first caller wins. Later calls with an
+// identical Config succeed without effect, while calls with a different one
+// return an error wrapping ErrAlreadyConfigured, so two packages that
+// configure crystal from their init functions find out instead of one
+// silently overriding the other. Assignments made directly to the variables
+// are not tracked. ConfigureOnce is safe for concurrent use, but like the
+// variables themselves it must complete before generators are created.
+func ConfigureOnce(cfg Config) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	if configured != nil {
+		if *configured != cfg {
+			return fmt.Errorf("%w: have %+v, want %+v", ErrAlreadyConfigured, *configured, cfg)
+		}
+		return nil
+	}
+
+	Epoch = cfg.Epoch
+	Timebits = cfg.Timebits
+	Versionbits = cfg.Versionbits
+	LayoutVersion = cfg.LayoutVersion
+	Kindbits = cfg.Kindbits
+	Nodebits = cfg.Nodebits
+	Workerbits = cfg.Workerbits
+	configured = &cfg
+	return nil
+}
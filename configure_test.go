@@ -0,0 +1,40 @@
+package crystal
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConfigureOnce(t *testing.T) {
+	orig := DefaultLayout()
+	t.Cleanup(func() {
+		Epoch, Timebits, Nodebits = orig.Epoch, orig.Timebits, orig.Nodebits
+		Versionbits, LayoutVersion = orig.Versionbits, orig.Version
+		Kindbits, Workerbits = orig.Kindbits, orig.Workerbits
+
+		configMu.Lock()
+		configured = nil
+		configMu.Unlock()
+	})
+
+	cfg := Config{Epoch: orig.Epoch, Timebits: 44, Nodebits: 4}
+	if err := ConfigureOnce(cfg); err != nil {
+		t.Fatalf("ConfigureOnce() failed: %v", err)
+	}
+	if Timebits != 44 || Nodebits != 4 {
+		t.Fatalf("Timebits, Nodebits = %d, %d, want 44, 4", Timebits, Nodebits)
+	}
+
+	if err := ConfigureOnce(cfg); err != nil {
+		t.Fatalf("repeated identical ConfigureOnce() failed: %v", err)
+	}
+
+	other := cfg
+	other.Timebits = 42
+	if err := ConfigureOnce(other); !errors.Is(err, ErrAlreadyConfigured) {
+		t.Fatalf("conflicting ConfigureOnce() error = %v, want ErrAlreadyConfigured", err)
+	}
+	if Timebits != 44 {
+		t.Fatalf("Timebits = %d after conflicting call, want 44", Timebits)
+	}
+}
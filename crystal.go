@@ -2,6 +2,7 @@
 //
 // Bit Allocation (63 bits total, fits in int64):
 //   - Time: Configurable via Timebits (default 42) - Milliseconds since epoch
+//   - Node: Optional, configurable via Nodebits (default 0) - Node identifier
 //   - Step: Remaining bits (default 21) - Monotonic counter each millisecond
 //
 // Features:
@@ -13,16 +14,23 @@
 package crystal
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base32"
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash"
+	"io"
+	"math"
+	mrand "math/rand/v2"
 	"os"
 	"runtime"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -30,11 +38,36 @@ const (
 	totalBits   = 63
 	minTimebits = 40
 	maxTimebits = 48
+	// secondTimebits is the minimum (and WithSecondResolution's) time width
+	// at Second precision.
+	secondTimebits = 32
+	maxKindbits    = 8
+
+	maxVersionbits = 2
 )
 
 // ID represents a unique crystal identifier (63 bits, always positive)
 type ID int64
 
+// ErrClosed is returned when generating IDs from a closed Generator.
+var ErrClosed = errors.New("crystal: generator closed")
+
+// ErrOutOfRange is returned when a value cannot be represented as a
+// non-negative 63-bit ID.
+var ErrOutOfRange = errors.New("crystal: value out of range")
+
+// ErrFullWidth is returned when asking a WithFullWidth generator for a
+// 63-bit ID; use GenerateU instead.
+var ErrFullWidth = errors.New("crystal: generator issues full-width UIDs")
+
+// ErrBeforeEpoch is returned by generators created with WithStrictEpoch when
+// the clock reads earlier than the epoch.
+var ErrBeforeEpoch = errors.New("crystal: clock is before epoch")
+
+// ErrNoNodeBits is returned when a node-specific operation is used with a
+// layout that has no node bits.
+var ErrNoNodeBits = errors.New("crystal: layout has no node bits")
+
 const defaultEpochMillis = int64(1577836800000) // 2020-01-01 00:00:00 UTC
 
 // Package-level overrides applied when creating new generators.
@@ -43,10 +76,36 @@ var (
 	Epoch int64 = defaultEpochMillis
 	// Timebits controls how many bits are assigned to the timestamp (default 42, range 40-48).
 	Timebits = 42
+	// StrictTimebits makes New and NewGenerator fail when Timebits is outside
+	// 40-48 instead of clamping it into range (default false).
+	StrictTimebits = false
+	// TimeLocation is the time zone ID.Time reports timestamps in (default
+	// UTC; nil also means UTC). CLIs may set it to time.Local.
+	TimeLocation = time.UTC
+	// Versionbits reserves the top bits of every ID for a layout-version tag
+	// (default 0, at most 2; see RegisterLayout).
+	Versionbits = 0
+	// LayoutVersion is the version tag new generators stamp into IDs when
+	// Versionbits is set.
+	LayoutVersion uint8
+	// Kindbits controls how many bits, taken from the sequence, carry an
+	// entity-kind tag (default 0, at most 8; see WithKind).
+	Kindbits = 0
+	// Nodebits controls how many bits, taken from the sequence, carry a node
+	// identifier (default 0, leaving at least one sequence bit).
+	Nodebits = 0
+	// Workerbits splits the node field: its low Workerbits bits hold a worker
+	// and the remaining high bits a datacenter (default 0).
+	Workerbits = 0
 	// base32Encoding uses Crockford alphabet in lowercase (excludes I, L, O, U)
 	//
 	//nolint:gochecknoglobals
-	base32Encoding = base32.NewEncoding("0123456789abcdefghjkmnpqrstvwxyz").WithPadding(base32.NoPadding)
+	base32Encoding = base32.NewEncoding(base32Alphabet).WithPadding(base32.NoPadding)
+	// packageInit records when the package was initialized, in nanoseconds
+	// since the Unix epoch; it stands in for the process start time.
+	//
+	//nolint:gochecknoglobals
+	packageInit = time.Now().UnixNano()
 )
 
 // Generator creates unique IDs with automatic node calculation
@@ -55,58 +114,663 @@ type Generator struct {
 	step       uint64
 	lastMillis int64
 	seed       [32]byte
+	clock      Clock
+	layout     Layout
+	node       uint64
+	kind       uint64
+
+	// seedHash, if set, replaces SHA-256 for deriving seed from the host (see
+	// WithSeedHash).
+	seedHash func() hash.Hash
+
+	// salt and entropy feed initCounter alongside seed.
+	salt    []byte
+	entropy io.Reader
+
+	// clusterSecret is folded into salt once the options are applied (see
+	// WithClusterSecret).
+	clusterSecret []byte
+
+	// fast, when non-nil, replaces entropy and SHA-256 for per-millisecond
+	// counter seeds (see WithFastReseed).
+	fast *mrand.PCG
+
+	// randFailures counts failed reads from entropy (see RandFailures).
+	randFailures atomic.Uint64
+
+	// shortSecond and shortStep hold the GenerateShort sequence.
+	shortSecond int64
+	shortStep   uint64
+
+	// childStep is the GenerateChild sequence, seeded on first use.
+	childStep uint64
+	childInit bool
+
+	// lastClock is the latest clock reading seen by Generate, used to measure
+	// backward jumps independently of milliseconds borrowed ahead of the clock.
+	lastClock int64
+	// maxDrift records the largest backward clock jump observed, in
+	// milliseconds.
+	maxDrift atomic.Int64
+	// behind is set while the clock reads earlier than lastClock.
+	behind bool
+
+	// generated, rollovers and clockBackwards feed MetricsSnapshot and
+	// ReadStats. They are only written with mu held but may be read without.
+	generated      atomic.Uint64
+	rollovers      atomic.Uint64
+	clockBackwards atomic.Uint64
+
+	// topology is non-nil while WithDatacenter/WithWorker settings wait to be
+	// applied to the layout.
+	topology *topology
+
+	// strictEpoch rejects generation before the epoch (see WithStrictEpoch).
+	strictEpoch bool
+
+	// logger, if set, receives rate-limited warnings about slow rollovers;
+	// lastWarn is when the last one was logged.
+	logger   Logger
+	lastWarn time.Time
+
+	// debug asserts monotonicity in crystaldebug builds.
+	debug debugState
+
+	// reseedInterval, if set, forces a counter reseed at least that often;
+	// reseedDue is set when the next ID must reseed (see WithReseedInterval).
+	reseedInterval time.Duration
+	reseedDue      bool
+
+	// reserve is non-nil when the generator hands out IDs from a window of
+	// pre-reserved milliseconds (see WithReservedWindow).
+	reserve *reservation
+
+	// logical is the AdvanceTo time, used instead of the clock once
+	// logicalSet.
+	logical    int64
+	logicalSet bool
+
+	// seen, if set, is checked for duplicates (see WithDedupeCheck).
+	seen Seen
+
+	// history, if set, remembers the latest IDs (see WithHistory).
+	history *history
+
+	// randSeq, if set, replaces the counter with random steps (see
+	// WithRandomSequence).
+	randSeq *randomSequence
+
+	// limiter, if set, paces generation (see WithRateLimit).
+	limiter *tokenBucket
+
+	// byteOrder, if set, replaces big-endian in the generator's encoders
+	// (see WithByteOrder).
+	byteOrder binary.ByteOrder
+
+	// paused is non-nil while the generator is paused and is closed on
+	// Resume (see Pause).
+	paused chan struct{}
+
+	// warmup guards Warmup.
+	warmup sync.Once
+
+	// closed is set by Close; stop and background coordinate the shutdown of
+	// background goroutines.
+	closed     bool
+	closeOnce  sync.Once
+	stop       chan struct{}
+	background sync.WaitGroup
 }
 
 // New creates a new Generator using the current package-level configuration.
-func New() *Generator {
-	seed := calculateNodeSeed()
+// It panics if any of the supplied options is invalid; use NewGenerator to
+// handle option errors instead.
+func New(opts ...Option) *Generator {
+	g, err := NewGenerator(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// NewGenerator creates a new Generator using the current package-level
+// configuration and the supplied options.
+func NewGenerator(opts ...Option) (*Generator, error) {
+	return newGenerator(nil, rand.Reader, opts)
+}
+
+// NewWithSeed creates a Generator from an explicit seed, typically one
+// obtained from another generator's Seed method. Instead of crypto/rand it
+// draws counter entropy from a deterministic stream derived from the seed, so
+// two generators created with the same seed, options and (injected) clock
+// produce identical sequences. Such sequences are predictable: use
+// NewWithSeed for test fixtures, not for production IDs. It panics if any of
+// the supplied options is invalid.
+func NewWithSeed(seed [32]byte, opts ...Option) *Generator {
+	g, err := newGenerator(&seed, newSeedStream(seed), opts)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// newGenerator creates a Generator from seed and entropy and applies opts. A
+// nil seed is derived from the host once the options have chosen the hash.
+func newGenerator(seed *[32]byte, entropy io.Reader, opts []Option) (*Generator, error) {
+	g := &Generator{
+		clock:   currentClock(),
+		layout:  DefaultLayout(),
+		entropy: entropy,
+		stop:    make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		if err := opt(g); err != nil {
+			return nil, err
+		}
+	}
+
+	if min := g.layout.minTimebits(); StrictTimebits && (g.layout.Timebits < min || g.layout.Timebits > maxTimebits) {
+		return nil, fmt.Errorf("%w: timebits %d not in %d-%d", ErrOutOfRange, g.layout.Timebits, min, maxTimebits)
+	}
+
+	if g.topology != nil {
+		if err := g.topology.apply(g); err != nil {
+			return nil, err
+		}
+		g.topology = nil
+	}
+
+	if seed != nil {
+		g.seed = *seed
+	} else {
+		newHash := g.seedHash
+		if newHash == nil {
+			newHash = sha256.New
+		}
+		g.seed = hostSeed(newHash)
+	}
+
+	if len(g.clusterSecret) > 0 {
+		g.salt = clusterSalt(g.salt, g.clusterSecret)
+		g.clusterSecret = nil
+	}
+
+	// WithFastReseed only marks the generator; seed the PCG now that salt and
+	// entropy are final.
+	if g.fast != nil {
+		g.fast = newFastSource(g.seed, g.salt, g.entropy)
+	}
+
+	if l := g.layout; l.Kindbits > 0 && l.timebits()+l.Kindbits+l.Nodebits >= l.fieldBits() {
+		return nil, fmt.Errorf("%d kind bits and %d node bits leave no sequence bits", l.Kindbits, l.Nodebits)
+	}
+
+	if g.node > g.layout.nodeMask() {
+		return nil, fmt.Errorf("node %d does not fit in %d node bits", g.node, g.layout.nodebits())
+	}
+
+	if g.layout.versionbits() > 0 {
+		if err := RegisterLayout(g.layout); err != nil {
+			return nil, err
+		}
+	}
 
-	return &Generator{
-		seed:       seed,
-		step:       initCounter(seed),
-		lastMillis: epochMillis(),
+	if now := g.layout.ticksSince(g.clock.Now()); now > int64(g.layout.timeMask()) { //nolint:gosec
+		return nil, fmt.Errorf("%w: clock %s is beyond the layout's time range, which ends %s",
+			ErrOutOfRange, g.clock.Now().UTC(), g.layout.timeAt(int64(g.layout.timeMask())).UTC()) //nolint:gosec
 	}
+
+	g.step = g.newCounter()
+	g.lastMillis = g.epochMillis()
+	g.lastClock = g.lastMillis
+
+	if g.reserve != nil {
+		g.reserve.start(g)
+	}
+	if g.reseedInterval > 0 {
+		g.startReseeder()
+	}
+
+	return g, nil
 }
 
 // Epoch returns the configured epoch as time.Time. When unset it returns the
 // Unix epoch (0).
 func (g *Generator) Epoch() time.Time {
-	sec := Epoch / 1000
-	nsec := (Epoch % 1000) * int64(time.Millisecond)
+	epoch := g.layout.Epoch
+	sec := epoch / 1000
+	nsec := (epoch % 1000) * int64(time.Millisecond)
 	return time.Unix(sec, nsec).UTC()
 }
 
-// Generate creates and returns a unique ID
-func (g *Generator) Generate() ID {
-	now := epochMillis()
+// Seed returns a copy of the 32-byte seed the generator mixes into its counter
+// seeds. Pass it to NewWithSeed to create a reproducible clone.
+func (g *Generator) Seed() [32]byte {
+	return g.seed
+}
+
+// SetEntropy replaces the randomness source mixed into future counter seeds,
+// e.g. to rotate it after a reseed event without restarting. A nil reader
+// restores crypto/rand. The reader is only used with the generator lock held.
+func (g *Generator) SetEntropy(r io.Reader) {
+	if r == nil {
+		r = rand.Reader
+	}
 
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	mask := currentStepMask()
-	shift := currentTimeShift()
+	g.entropy = r
+}
 
-	if now < g.lastMillis {
-		now = g.lastMillis
+// Layout returns the bit layout the generator packs IDs with.
+func (g *Generator) Layout() Layout {
+	return g.layout
+}
+
+// Generate creates and returns a unique ID, blocking while the generator is
+// paused. It panics with the error GenerateSafe would return, such as
+// ErrClosed once the generator has been closed or ErrFullWidth for a
+// WithFullWidth generator; use GenerateSafe to receive the error instead.
+func (g *Generator) Generate() ID {
+	if g.layout.FullWidth {
+		panic(ErrFullWidth)
+	}
+	id, err := g.generateWait(context.Background(), g.node)
+	if err != nil {
+		panic(err)
 	}
+	return id
+}
 
-	if now == g.lastMillis {
-		g.step = (g.step + 1) & mask
-		if g.step == 0 {
-			for now <= g.lastMillis {
-				runtime.Gosched()
-				now = epochMillis()
-			}
-			g.step = initCounter(g.seed)
+// GenerateSafe creates and returns a unique ID, or an error (such as
+// ErrClosed) when the generator cannot issue one.
+func (g *Generator) GenerateSafe() (ID, error) {
+	return g.generateSafe(g.node)
+}
+
+// GenerateString creates a unique ID and returns its base32 form in the
+// generator's byte order (see WithByteOrder).
+func (g *Generator) GenerateString() string {
+	return g.Base32(g.Generate())
+}
+
+// RequestID creates a unique ID and returns it as a 13 character base32
+// string in the generator's byte order, like GenerateString, for correlating
+// the log lines of an HTTP request or job. The crystalhttp package provides
+// middleware that attaches one to each request.
+func (g *Generator) RequestID() string {
+	return g.GenerateString()
+}
+
+// GenerateHex creates a unique ID and returns its hexadecimal form in the
+// generator's byte order (see WithByteOrder).
+func (g *Generator) GenerateHex() string {
+	return g.Hex(g.Generate())
+}
+
+// GenerateInt64 creates a unique ID and returns it as an int64.
+func (g *Generator) GenerateInt64() int64 {
+	return g.Generate().Int64()
+}
+
+// GenerateWithForms creates a unique ID and returns it together with its
+// base32 and int64 forms, for write paths that persist more than one of them.
+// The base32 form matches the generator's Base32 method, so it follows
+// WithByteOrder. It is encoded once, straight into the returned string, so
+// the call costs a single allocation.
+func (g *Generator) GenerateWithForms() (ID, string, int64) {
+	id := g.Generate()
+	var b [base32Len]byte
+	encodeBase32(&b, g.ordered(id))
+	return id, string(b[:]), id.Int64()
+}
+
+// Warmup primes the generator so latency-sensitive callers do not pay
+// one-time costs on their first request: it derives a counter seed (running
+// the entropy read and SHA-256 once) and issues and discards one ID. Only the
+// first call does any work; Warmup is safe to call concurrently with itself
+// and with Generate.
+func (g *Generator) Warmup() {
+	g.warmup.Do(func() {
+		g.mu.Lock()
+		_ = g.newCounter()
+		g.mu.Unlock()
+
+		_, _ = g.generate(g.node)
+	})
+}
+
+// Close stops the generator's background goroutines (such as the
+// WithReservedWindow refiller) and waits for them to exit, after which the
+// generator is safe to discard. Generating IDs after Close fails with
+// ErrClosed. Calling Close more than once is harmless.
+func (g *Generator) Close() error {
+	g.closeOnce.Do(func() {
+		g.mu.Lock()
+		g.closed = true
+		g.resumeLocked()
+		g.mu.Unlock()
+
+		close(g.stop)
+		g.background.Wait()
+	})
+	return nil
+}
+
+// goBackground runs fn in a goroutine that Close signals through stop and
+// waits for.
+func (g *Generator) goBackground(fn func(stop <-chan struct{})) {
+	g.background.Add(1)
+	go func() {
+		defer g.background.Done()
+		fn(g.stop)
+	}()
+}
+
+// GenerateForNode creates a unique ID stamped with nodeID instead of the
+// generator's own node, e.g. to attribute a backfilled record to a particular
+// worker. It requires a layout with node bits and a nodeID that fits them.
+func (g *Generator) GenerateForNode(nodeID uint16) (ID, error) {
+	if g.layout.nodebits() == 0 {
+		return 0, ErrNoNodeBits
+	}
+	if uint64(nodeID) > g.layout.nodeMask() {
+		return 0, fmt.Errorf("node %d does not fit in %d node bits", nodeID, g.layout.nodebits())
+	}
+	return g.generateSafe(uint64(nodeID))
+}
+
+// GenerateAfter creates a unique ID strictly greater than min, e.g. to stay
+// above a caller-supplied watermark for optimistic concurrency. When the
+// clock has not yet reached min's timestamp, the generator borrows future
+// milliseconds: it continues from min's millisecond and subsequent IDs keep
+// increasing from there until the clock catches up. A WithReservedWindow
+// generator borrows in the same way, so for a future min its IDs may run
+// further ahead of the clock than the window. It panics with ErrClosed if the
+// generator has been closed, and with an error wrapping ErrOutOfRange when no
+// ID above min fits the layout's time range (e.g. min is MaxID).
+func (g *Generator) GenerateAfter(min ID) ID {
+	if g.layout.FullWidth {
+		panic(ErrFullWidth)
+	}
+
+	g.lockUnpaused()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		panic(ErrClosed)
+	}
+
+	now := g.currentMillis()
+	g.observeClock(now)
+	id := g.nextLocked(now, g.node)
+	if id > min {
+		return id
+	}
+
+	// The clock is at or behind min's millisecond, so lastMillis is too.
+	l := g.layout
+	minMillis := l.millis(min)
+	minNode := l.Node(min)
+
+	next, step := minMillis, g.step
+	switch {
+	case g.randSeq != nil:
+		// Random steps cannot be placed after min's within its millisecond.
+		next = minMillis + 1
+	case g.node > minNode:
+		// Any step in min's millisecond sorts above min.
+	case g.node == minNode:
+		// Continue the sequence just past min's step.
+		if g.lastMillis < minMillis || g.step < l.Step(min) {
+			step = l.Step(min)
+		}
+	default:
+		// Every step in min's millisecond sorts below min.
+		next, step = minMillis+1, g.newCounter()
+	}
+
+	// Borrowing must not run past the layout's last millisecond, where the
+	// time field would wrap to the epoch.
+	if last := int64(l.timeMask()); next > last || (next == last && step == l.stepMask() && g.randSeq == nil) { //nolint:gosec
+		panic(fmt.Errorf("%w: no ID above %d fits the layout's time range", ErrOutOfRange, min))
+	}
+	g.lastMillis, g.step = next, step
+
+	return g.nextLocked(g.lastMillis, g.node)
+}
+
+// generateSafe creates a unique ID carrying the given node.
+func (g *Generator) generateSafe(node uint64) (ID, error) {
+	if g.layout.FullWidth {
+		return 0, ErrFullWidth
+	}
+	return g.generate(node)
+}
+
+// generate creates a unique value carrying the given node, regardless of the
+// layout's width.
+func (g *Generator) generate(node uint64) (ID, error) {
+	return g.generateContext(context.Background(), node)
+}
+
+// generateContext is generate, giving up if ctx is done while waiting for the
+// rate limiter.
+func (g *Generator) generateContext(ctx context.Context, node uint64) (ID, error) {
+	if g.limiter != nil {
+		if err := g.limiter.wait(ctx); err != nil {
+			return 0, err
 		}
-	} else {
-		g.step = initCounter(g.seed)
 	}
 
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		return 0, ErrClosed
+	}
+	if g.paused != nil {
+		return 0, ErrPaused
+	}
+	if g.strictEpoch && g.clock.Now().UnixMilli() < g.layout.Epoch {
+		return 0, ErrBeforeEpoch
+	}
+
+	// Read the clock under the lock: a reading taken before it could be
+	// older than one another goroutine has since recorded, and would count
+	// as a backward jump.
+	now := g.currentMillis()
+	g.observeClock(now)
+	return g.nextChecked(now, node)
+}
+
+// observeClock records the clock reading now, noting any backward jump. It
+// must be called with g.mu held.
+func (g *Generator) observeClock(now int64) {
+	if now < g.lastClock {
+		if !g.behind {
+			g.clockBackwards.Add(1)
+			g.behind = true
+		}
+		g.observeDrift(g.lastClock - now)
+		return
+	}
+	g.behind = false
+	g.lastClock = now
+}
+
+// nextLocked advances the sequence for the millisecond now and returns the
+// resulting ID carrying node. It must be called with g.mu held.
+//
+// The invariant is that the ID's millisecond never falls below g.lastMillis
+// and, when it equals g.lastMillis, its step exceeds the previous one, so
+// each ID is strictly greater than the last (random sequences excepted).
+func (g *Generator) nextLocked(now int64, node uint64) ID {
+	switch {
+	case g.randSeq != nil:
+		now = g.randomStep(max(now, g.lastMillis))
+	case now > g.lastMillis:
+		g.step = g.newCounter()
+	case g.reseedDue:
+		now = g.lastMillis + 1
+		g.step = g.newCounter()
+	case now < g.lastMillis:
+		// The clock went backwards: stay on the last millisecond and keep
+		// counting, borrowing the following millisecond on rollover rather
+		// than waiting for the clock to catch up.
+		now = g.advanceStep()
+	default:
+		now = g.advanceStep()
+	}
+	g.reseedDue = false
+
 	g.lastMillis = now
+	g.generated.Add(1)
+
+	id := g.layout.withKind(g.layout.compose(now, node, g.step), g.kind)
+	if node == g.node && !g.layout.ScatteredTime && g.randSeq == nil {
+		g.debug.check(id)
+	}
+	if g.history != nil {
+		g.history.record(id)
+	}
+	return id
+}
+
+// advanceStep moves to the next step of g.lastMillis and returns that
+// millisecond. Once the step space is exhausted it moves on to the millisecond
+// chosen by nextMillis with a fresh counter instead. It must be called with
+// g.mu held.
+func (g *Generator) advanceStep() int64 {
+	g.step = (g.step + 1) & g.layout.stepMask()
+	if g.step != 0 {
+		return g.lastMillis
+	}
+	g.rollovers.Add(1)
+	now := g.nextMillis()
+	g.step = g.newCounter()
+	return now
+}
+
+// newCounter returns a fresh starting value for the sequence counter. Once the
+// generator is shared it must be called with g.mu held, since the entropy
+// reader may be swapped by SetEntropy.
+func (g *Generator) newCounter() uint64 {
+	if g.fast != nil {
+		return g.fast.Uint64() & g.layout.stepSeedMask()
+	}
+	return initCounter(g.seed, g.salt, g.entropy, g.layout.stepSeedMask(), &g.randFailures)
+}
+
+// currentMillis returns the millisecond a new ID should be stamped with. With a
+// reserved window this is the refiller's last clock reading, so the hot path
+// never consults the wall clock itself. After AdvanceTo it is the logical
+// time. It must be called with g.mu held.
+func (g *Generator) currentMillis() int64 {
+	if g.logicalSet {
+		return g.logical
+	}
+	if g.reserve != nil {
+		return g.reserve.now.Load()
+	}
+	return g.epochMillis()
+}
+
+// nextMillis returns the millisecond to move to once the step space of
+// g.lastMillis is exhausted. Without a reserved window it waits for the clock
+// to advance; with one it borrows the following millisecond from the window.
+// When the generator is already ahead of the clock (it borrowed milliseconds
+// or the clock went backwards) waiting could take arbitrarily long, so it
+// borrows the following millisecond instead.
+func (g *Generator) nextMillis() int64 {
+	if g.logicalSet {
+		return g.lastMillis + 1
+	}
+	if g.reserve != nil {
+		return g.reserve.next(g.lastMillis)
+	}
+
+	now := g.epochMillis()
+	if now < g.lastMillis {
+		return g.lastMillis + 1
+	}
+	var start time.Time
+	if g.logger != nil {
+		start = time.Now()
+	}
+	for now <= g.lastMillis {
+		runtime.Gosched()
+		now = g.epochMillis()
+	}
+	if g.logger != nil {
+		g.logRolloverWait(start)
+	}
+	return now
+}
 
-	return ID((uint64(now) << shift) | //nolint:gosec
-		(g.step & mask))
+// RandFailures returns how many reads from the entropy source have failed.
+// Each counter seed retries a failing source a few times before falling back
+// to a timestamp-derived seed, so an occasional failure is harmless, but a
+// steadily growing count points at a systemic entropy problem.
+func (g *Generator) RandFailures() uint64 {
+	return g.randFailures.Load()
+}
+
+// MaxBackwardDrift returns the largest backward clock jump the generator has
+// observed. Generate clamps such jumps to the last issued millisecond, so a
+// non-zero value points at clock trouble (e.g. NTP stepping the clock back)
+// rather than at duplicate IDs.
+func (g *Generator) MaxBackwardDrift() time.Duration {
+	return time.Duration(g.maxDrift.Load()) * g.layout.tick()
+}
+
+// RemainingThisMillis returns how many more IDs the generator can issue in the
+// millisecond of its last ID before the sequence wraps and it has to move on
+// to the next millisecond. Adaptive rate limiters can use it to back off
+// before hitting rollover.
+func (g *Generator) RemainingThisMillis() uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.layout.stepMask() - g.step
+}
+
+// RemainingThisSecond returns a lower bound on how many more IDs the
+// generator can issue before the clock reaches the next whole second: what
+// is left of the current millisecond's sequence plus the guaranteed capacity
+// of every millisecond still to come in the second. Each new millisecond's
+// sequence starts at a random value in the lower half of the step space, so
+// the guarantee is half the step space per millisecond. Batch schedulers can
+// use it to size work aligned to second boundaries.
+func (g *Generator) RemainingThisSecond() uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	l := g.layout
+	now := g.currentMillis()
+	fresh := l.stepMask() - l.stepSeedMask()
+
+	perSecond := int64(time.Second / l.tick())
+	into := int64(l.timeAt(now).Nanosecond()) / int64(l.tick())
+	left := uint64(perSecond - 1 - into) //nolint:gosec
+
+	if now == g.lastMillis {
+		return l.stepMask() - g.step + left*fresh
+	}
+	return (left + 1) * fresh
+}
+
+// observeDrift records a backward clock jump of d milliseconds if it exceeds
+// the largest one seen so far.
+func (g *Generator) observeDrift(d int64) {
+	for {
+		cur := g.maxDrift.Load()
+		if d <= cur || g.maxDrift.CompareAndSwap(cur, d) {
+			return
+		}
+	}
 }
 
 // Int64 returns the ID as an int64
@@ -114,12 +778,66 @@ func (id ID) Int64() int64 {
 	return int64(id)
 }
 
-// Time returns the timestamp embedded in the ID
+// Uint64 returns the ID as a uint64. This is the single place the signed ID is
+// converted to its unsigned form: generated IDs are never negative (bit 63 is
+// always clear), so the conversion preserves the value. A negative ID, which
+// can only be constructed by hand, maps to its two's-complement bit pattern.
+func (id ID) Uint64() uint64 {
+	return uint64(id) //nolint:gosec
+}
+
+// Time returns the timestamp embedded in the ID, in TimeLocation.
 func (id ID) Time() time.Time {
-	millis := (int64(id) >> currentTimeShift()) + Epoch
-	sec := millis / 1000
-	nsec := (millis % 1000) * int64(time.Millisecond)
-	return time.Unix(sec, nsec)
+	loc := TimeLocation
+	if loc == nil {
+		loc = time.UTC
+	}
+	return layoutFor(id).Time(id).In(loc)
+}
+
+// Kind returns the entity-kind tag embedded in the ID, or 0 when Kindbits is
+// unset.
+func (id ID) Kind() uint8 {
+	return layoutFor(id).Kind(id)
+}
+
+// Node returns the node identifier embedded in the ID, or 0 when Nodebits is
+// unset.
+func (id ID) Node() uint64 {
+	return layoutFor(id).Node(id)
+}
+
+// Datacenter returns the datacenter field of the ID's node, as split by the
+// package-level Nodebits and Workerbits.
+func (id ID) Datacenter() uint8 {
+	return layoutFor(id).Datacenter(id)
+}
+
+// Worker returns the worker field of the ID's node, as split by the
+// package-level Workerbits.
+func (id ID) Worker() uint8 {
+	return layoutFor(id).Worker(id)
+}
+
+// KeyParts splits the ID into big-endian timestamp and sequence bytes using
+// the package-level layout. See Layout.KeyParts.
+func (id ID) KeyParts() (timePrefix []byte, seqSuffix []byte) {
+	return layoutFor(id).KeyParts(id)
+}
+
+// Age returns the time elapsed since the ID's embedded timestamp.
+func (id ID) Age() time.Duration {
+	return time.Since(id.Time())
+}
+
+// Before reports whether the ID's embedded timestamp is before t.
+func (id ID) Before(t time.Time) bool {
+	return id.Time().Before(t)
+}
+
+// After reports whether the ID's embedded timestamp is after t.
+func (id ID) After(t time.Time) bool {
+	return id.Time().After(t)
 }
 
 // String returns the base32 encoded string representation
@@ -129,18 +847,14 @@ func (id ID) String() string {
 
 // Base32 returns the base32 encoded string representation
 func (id ID) Base32() string {
-	var b [8]byte
-	//nolint:gosec
-	binary.BigEndian.PutUint64(b[:], uint64(id))
-	return base32Encoding.EncodeToString(b[:])
+	var b [base32Len]byte
+	return string(id.AppendBase32(b[:0]))
 }
 
 // Hex returns the lowercase hexadecimal string representation
 func (id ID) Hex() string {
-	var b [8]byte
-	//nolint:gosec
-	binary.BigEndian.PutUint64(b[:], uint64(id))
-	return hex.EncodeToString(b[:])
+	var b [16]byte
+	return string(id.AppendHex(b[:0]))
 }
 
 // ParseInt64 converts an int64 to an ID
@@ -148,6 +862,21 @@ func ParseInt64(i int64) ID {
 	return ID(i)
 }
 
+// Integer is the set of integer types accepted by Parse.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Parse converts an integer of any width to an ID. It returns ErrOutOfRange
+// when v is negative or does not fit in 63 bits.
+func Parse[T Integer](v T) (ID, error) {
+	if v < 0 || uint64(v) > math.MaxInt64 {
+		return 0, ErrOutOfRange
+	}
+	return ID(v), nil
+}
+
 // ParseString parses a base32 encoded string into an ID
 func ParseString(s string) (ID, error) {
 	return ParseBase32(s)
@@ -166,23 +895,39 @@ func ParseBase32(s string) (ID, error) {
 	return ID(binary.BigEndian.Uint64(b)), nil
 }
 
-// ParseHex parses a hexadecimal string into an ID.
+// ParseHex parses a hexadecimal string into an ID. Hex always produces 16
+// characters, but inputs whose leading zeros were trimmed (1 to 15
+// characters, odd lengths included) are left-padded with zeros first, so
+// "2a" parses as ID 42. Empty inputs and inputs longer than 16 characters
+// are rejected.
 func ParseHex(s string) (ID, error) {
-	b, err := hex.DecodeString(s)
-	if err != nil {
-		return 0, err
+	return parseHex(s)
+}
+
+// parseHex implements ParseHex and ParseHexBytes without allocating.
+func parseHex[T string | []byte](s T) (ID, error) {
+	var padded [16]byte
+	if len(s) == 0 || len(s) > len(padded) {
+		return 0, fmt.Errorf("invalid hex length: %d characters", len(s))
 	}
-	if len(b) != 8 {
-		return 0, fmt.Errorf("invalid hex length: %d", len(b))
+	n := len(padded) - len(s)
+	for i := 0; i < n; i++ {
+		padded[i] = '0'
 	}
-	//nolint:gosec
-	return ID(binary.BigEndian.Uint64(b)), nil
+	copy(padded[n:], s)
+
+	var raw [8]byte
+	if _, err := hex.Decode(raw[:], padded[:]); err != nil {
+		return 0, err
+	}
+	return FromBytes(raw), nil
 }
 
-// epochMillis returns milliseconds since the configured epoch, clamped to zero
-// when the clock drifts backwards.
-func epochMillis() int64 {
-	millis := time.Now().UnixMilli() - Epoch
+// epochMillis returns milliseconds (or the layout's Precision units) since the
+// configured epoch according to the generator's clock, clamped to zero when
+// the clock reads before the epoch.
+func (g *Generator) epochMillis() int64 {
+	millis := g.layout.ticksSince(g.clock.Now())
 	if millis < 0 {
 		return 0
 	}
@@ -192,60 +937,63 @@ func epochMillis() int64 {
 // normalizedTimebits clamps the exported Timebits knob into the supported range
 // (40-48 bits) so it always leaves room for at least one sequence bit.
 func normalizedTimebits() int {
-	t := Timebits
-	if t < minTimebits {
-		t = minTimebits
-	}
-	if t > maxTimebits {
-		t = maxTimebits
-	}
-	return t
+	return DefaultLayout().timebits()
 }
 
 // currentStepBits returns how many bits are currently available for the
-// sequence component (total bits minus time bits, with a minimum of one).
+// sequence component (total bits minus time and node bits, with a minimum of
+// one).
 func currentStepBits() int {
-	bits := totalBits - normalizedTimebits()
-	if bits < 1 {
-		return 1
-	}
-	return bits
+	return DefaultLayout().stepBits()
 }
 
-// currentTimeShift converts the current step width into the shift applied when
-// packing or unpacking the timestamp.
+// currentTimeShift returns the shift applied when packing or unpacking the
+// timestamp with the current package-level layout.
 func currentTimeShift() uint {
-	return uint(currentStepBits())
+	return DefaultLayout().timeShift()
 }
 
 // currentStepMask returns a mask that isolates the sequence bits in the ID.
 func currentStepMask() uint64 {
-	return (uint64(1) << currentTimeShift()) - 1
+	return DefaultLayout().stepMask()
 }
 
 // currentStepSeedMask returns a mask that caps the initial counter seed to the
 // lower half of the step's range so we never start near the rollover boundary.
 func currentStepSeedMask() uint64 {
-	bits := currentStepBits()
-	if bits <= 1 {
-		return 0
-	}
-	return (uint64(1) << uint(bits-1)) - 1
+	return DefaultLayout().stepSeedMask()
 }
 
-// calculateNodeSeed derives entropy from the hostname + PID hash, returning the
-// full SHA-256 sum for use when seeding the counter.
+// calculateNodeSeed derives entropy from the hostname + PID + process start
+// time hash, returning the full SHA-256 sum for use when seeding the counter.
+// The start time keeps seeds distinct when a PID is reused across quick
+// restarts on the same host.
 func calculateNodeSeed() [32]byte {
+	return hostSeed(sha256.New)
+}
+
+// hostSeed is calculateNodeSeed with the hash supplied by newHash.
+func hostSeed(newHash func() hash.Hash) [32]byte {
 	machine, err := os.Hostname()
 	if err != nil || machine == "" {
 		machine = "unknown"
 	}
 
-	pid := os.Getpid()
+	return nodeSeedHash(newHash, machine, os.Getpid(), processStartTime())
+}
 
-	h := sha256.New()
+// nodeSeed hashes the host, PID and process start time into a seed.
+func nodeSeed(machine string, pid int, start int64) [32]byte {
+	return nodeSeedHash(sha256.New, machine, pid, start)
+}
+
+// nodeSeedHash is nodeSeed with the hash supplied by newHash. Sums shorter
+// than 32 bytes are zero-padded and longer ones truncated.
+func nodeSeedHash(newHash func() hash.Hash, machine string, pid int, start int64) [32]byte {
+	h := newHash()
 	h.Write([]byte(machine))
 	h.Write([]byte(strconv.Itoa(pid)))
+	h.Write([]byte(strconv.FormatInt(start, 10)))
 	hash := h.Sum(nil)
 
 	var seed [32]byte
@@ -253,27 +1001,117 @@ func calculateNodeSeed() [32]byte {
 	return seed
 }
 
+// processStartTime identifies when the current process started, using the
+// operating system's record where available and the package initialization
+// time otherwise.
+func processStartTime() int64 {
+	if start, ok := osProcessStart(); ok {
+		return start
+	}
+	return packageInit
+}
+
 // initCounter returns a random seed for the counter. It mixes the 32-byte host
-// digest with fresh crypto/rand output, hashes the combination, and then caps
-// the result with currentStepSeedMask so the starting position always falls in
-// the lower half of the sequence space (avoiding immediate rollover).
-func initCounter(seed [32]byte) uint64 {
-	mask := currentStepSeedMask()
+// digest and the optional salt with fresh output from entropy, hashes the
+// combination, and then caps the result with mask (a layout's stepSeedMask) so
+// the starting position always falls in the lower half of the sequence space
+// (avoiding immediate rollover). Failed entropy reads are retried up to
+// entropyAttempts times and counted in failures, if non-nil.
+func initCounter(seed [32]byte, salt []byte, entropy io.Reader, mask uint64, failures *atomic.Uint64) uint64 {
 	if mask == 0 {
 		return 0
 	}
 
 	var randBuf [32]byte
-	if _, err := rand.Read(randBuf[:]); err != nil {
-		// Fallback to timestamp if crypto/rand fails
+	if !readEntropy(entropy, randBuf[:], failures) {
+		// Fallback to timestamp if the entropy source fails
+		salted := saltedSeed(seed, salt)
 		//nolint:gosec
-		fallback := (uint64(time.Now().UnixNano()) ^ binary.BigEndian.Uint64(seed[0:8])) & mask
+		fallback := (uint64(time.Now().UnixNano()) ^ binary.BigEndian.Uint64(salted[0:8])) & mask
 		return fallback
 	}
 
 	h := sha256.New()
 	h.Write(seed[:])
+	h.Write(salt)
 	h.Write(randBuf[:])
 	sum := h.Sum(nil)
 	return binary.BigEndian.Uint64(sum) & mask
 }
+
+// entropyAttempts is how often readEntropy tries the entropy source before
+// giving up.
+const entropyAttempts = 3
+
+// readEntropy fills buf from entropy, retrying transient failures. Each failed
+// attempt is counted in failures, if non-nil.
+func readEntropy(entropy io.Reader, buf []byte, failures *atomic.Uint64) bool {
+	for i := 0; i < entropyAttempts; i++ {
+		if _, err := io.ReadFull(entropy, buf); err == nil {
+			return true
+		}
+		if failures != nil {
+			failures.Add(1)
+		}
+	}
+	return false
+}
+
+// newFastSource returns a PCG generator seeded once from the salted seed and
+// 16 bytes of entropy, for use by WithFastReseed.
+func newFastSource(seed [32]byte, salt []byte, entropy io.Reader) *mrand.PCG {
+	salted := saltedSeed(seed, salt)
+	var randBuf [16]byte
+	if _, err := io.ReadFull(entropy, randBuf[:]); err != nil {
+		//nolint:gosec
+		binary.BigEndian.PutUint64(randBuf[:8], uint64(time.Now().UnixNano()))
+	}
+	return mrand.NewPCG(
+		binary.BigEndian.Uint64(salted[0:8])^binary.BigEndian.Uint64(randBuf[0:8]),
+		binary.BigEndian.Uint64(salted[8:16])^binary.BigEndian.Uint64(randBuf[8:16]),
+	)
+}
+
+// saltedSeed folds salt into seed. Without a salt the seed is returned as is.
+func saltedSeed(seed [32]byte, salt []byte) [32]byte {
+	if len(salt) == 0 {
+		return seed
+	}
+	h := sha256.New()
+	h.Write(seed[:])
+	h.Write(salt)
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// seedStream is a deterministic io.Reader that expands a seed into an endless
+// stream of SHA-256 blocks (SHA256(seed || counter)).
+type seedStream struct {
+	seed    [32]byte
+	counter uint64
+	block   [sha256.Size]byte
+	off     int
+}
+
+func newSeedStream(seed [32]byte) *seedStream {
+	return &seedStream{seed: seed, off: sha256.Size}
+}
+
+func (s *seedStream) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if s.off == sha256.Size {
+			var buf [40]byte
+			copy(buf[:32], s.seed[:])
+			binary.BigEndian.PutUint64(buf[32:], s.counter)
+			s.block = sha256.Sum256(buf[:])
+			s.counter++
+			s.off = 0
+		}
+		c := copy(p[n:], s.block[s.off:])
+		s.off += c
+		n += c
+	}
+	return n, nil
+}
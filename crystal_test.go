@@ -1,6 +1,13 @@
 package crystal
 
 import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"hash/fnv"
+	"io"
+	"math"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -73,6 +80,34 @@ func TestIDMethods(t *testing.T) {
 	}
 }
 
+func TestIDAge(t *testing.T) {
+	gen := New()
+
+	fresh := gen.Generate()
+	if age := fresh.Age(); age < 0 || age > time.Second {
+		t.Errorf("fresh ID age unreasonable: %v", age)
+	}
+	if fresh.After(time.Now().Add(time.Second)) {
+		t.Error("fresh ID should not be after one second from now")
+	}
+	if !fresh.Before(time.Now().Add(time.Second)) {
+		t.Error("fresh ID should be before one second from now")
+	}
+
+	created := time.Now().Add(-time.Hour)
+	old := ID((created.UnixMilli() - Epoch) << currentTimeShift())
+
+	if age := old.Age(); age < time.Hour || age > time.Hour+time.Second {
+		t.Errorf("old ID age unreasonable: %v", age)
+	}
+	if !old.Before(time.Now().Add(-30 * time.Minute)) {
+		t.Error("old ID should be before 30 minutes ago")
+	}
+	if !old.After(created.Add(-time.Second)) {
+		t.Error("old ID should be after its creation time minus one second")
+	}
+}
+
 func TestParseString(t *testing.T) {
 	gen := New()
 
@@ -142,6 +177,33 @@ func TestParseHexInvalid(t *testing.T) {
 	}
 }
 
+func TestParseHexShort(t *testing.T) {
+	for _, id := range []ID{0, 1, 42, 0xabc, 0x0123456789abcdef >> 1} {
+		h := id.Hex()
+		if len(h) != 16 {
+			t.Fatalf("Hex() of %d = %q, want 16 characters", id, h)
+		}
+		for trimmed := strings.TrimLeft(h, "0"); len(trimmed) < len(h); trimmed = "0" + trimmed {
+			if trimmed == "" {
+				continue
+			}
+			parsed, err := ParseHex(trimmed)
+			if err != nil || parsed != id {
+				t.Errorf("ParseHex(%q) = %d, %v; want %d", trimmed, parsed, err, id)
+			}
+			if parsed, err := ParseHexBytes([]byte(trimmed)); err != nil || parsed != id {
+				t.Errorf("ParseHexBytes(%q) = %d, %v; want %d", trimmed, parsed, err, id)
+			}
+		}
+	}
+
+	for _, bad := range []string{"", "00000000000000000", "0x2a", "2g"} {
+		if _, err := ParseHex(bad); err == nil {
+			t.Errorf("ParseHex(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
 func TestParseInt64(t *testing.T) {
 	gen := New()
 
@@ -154,11 +216,57 @@ func TestParseInt64(t *testing.T) {
 	}
 }
 
+func TestUint64(t *testing.T) {
+	gen := New()
+
+	for i := 0; i < 1000; i++ {
+		id := gen.Generate()
+		u := id.Uint64()
+		if u > math.MaxInt64 {
+			t.Fatalf("Uint64() = %d exceeds 63 bits", u)
+		}
+		if parsed := ParseInt64(int64(u)); parsed != id { //nolint:gosec
+			t.Fatalf("ParseInt64(int64(Uint64())) = %d, want %d", parsed, id)
+		}
+	}
+}
+
+func TestParseGeneric(t *testing.T) {
+	gen := New()
+	id := gen.Generate()
+
+	if parsed, err := Parse(id.Int64()); err != nil || parsed != id {
+		t.Errorf("Parse(int64) = %d, %v, want %d", parsed, err, id)
+	}
+	if parsed, err := Parse(id.Uint64()); err != nil || parsed != id {
+		t.Errorf("Parse(uint64) = %d, %v, want %d", parsed, err, id)
+	}
+	if parsed, err := Parse(int32(12345)); err != nil || parsed != 12345 {
+		t.Errorf("Parse(int32) = %d, %v, want 12345", parsed, err)
+	}
+	if parsed, err := Parse(uint(7)); err != nil || parsed != 7 {
+		t.Errorf("Parse(uint) = %d, %v, want 7", parsed, err)
+	}
+	if parsed, err := Parse(uint8(255)); err != nil || parsed != 255 {
+		t.Errorf("Parse(uint8) = %d, %v, want 255", parsed, err)
+	}
+
+	if _, err := Parse(int16(-1)); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Parse(int16(-1)) error = %v, want ErrOutOfRange", err)
+	}
+	if _, err := Parse(uint64(math.MaxInt64) + 1); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Parse(MaxInt64+1) error = %v, want ErrOutOfRange", err)
+	}
+	if _, err := Parse(uint64(math.MaxUint64)); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Parse(MaxUint64) error = %v, want ErrOutOfRange", err)
+	}
+}
+
 func TestInitCounterRange(t *testing.T) {
 	seed := calculateNodeSeed()
 	mask := currentStepSeedMask()
 	for i := 0; i < 1000; i++ {
-		val := initCounter(seed)
+		val := initCounter(seed, nil, rand.Reader, mask, nil)
 		if mask == 0 {
 			if val != 0 {
 				t.Fatalf("expected initCounter to return 0 when mask is 0, got %d", val)
@@ -171,6 +279,140 @@ func TestInitCounterRange(t *testing.T) {
 	}
 }
 
+func TestNodeSeedProcessStart(t *testing.T) {
+	// Simulate two processes that reuse the same PID on the same host but
+	// start at different times (the package-init fallback path).
+	first := time.Now().UnixNano()
+	second := first + int64(time.Millisecond)
+
+	a := nodeSeed("host", 4242, first)
+	b := nodeSeed("host", 4242, second)
+	if a == b {
+		t.Fatal("expected different seeds for different process start times")
+	}
+
+	if nodeSeed("host", 4242, first) != a {
+		t.Fatal("expected nodeSeed to be deterministic")
+	}
+
+	if processStartTime() != processStartTime() {
+		t.Fatal("expected the process start time to be stable")
+	}
+}
+
+func TestWithSeedHash(t *testing.T) {
+	a := New(WithSeedHash(fnv.New128a)).Seed()
+	b := New(WithSeedHash(fnv.New128a)).Seed()
+	if a != b {
+		t.Fatal("expected a stable seed from the same hash")
+	}
+	if a == New().Seed() {
+		t.Fatal("expected the FNV seed to differ from the SHA-256 seed")
+	}
+
+	// The 16-byte FNV sum is zero-padded to the 32-byte seed.
+	if want := nodeSeedHash(fnv.New128a, "host", 1, 2); [16]byte(want[16:]) != [16]byte{} {
+		t.Fatalf("expected zero padding after the 16-byte sum, got %x", want)
+	}
+	if gen := New(WithSeedHash(fnv.New128a)); gen.Generate() <= 0 {
+		t.Fatal("expected a valid ID from an FNV-seeded generator")
+	}
+
+	if _, err := NewGenerator(WithSeedHash(nil)); err == nil {
+		t.Fatal("expected error for nil seed hash")
+	}
+}
+
+func TestTenantSalt(t *testing.T) {
+	seed := calculateNodeSeed()
+	entropy := bytes.Repeat([]byte{0x5a}, 32)
+
+	counter := func(opts ...Option) uint64 {
+		gen := New(opts...)
+		gen.seed = seed
+		gen.entropy = bytes.NewReader(entropy)
+		return gen.newCounter()
+	}
+
+	a := counter(WithTenantSalt([]byte("tenant-a")))
+	b := counter(WithTenantSalt([]byte("tenant-b")))
+	if a == b {
+		t.Fatalf("expected different initial counters for different salts, both %d", a)
+	}
+
+	if again := counter(WithTenantSalt([]byte("tenant-a"))); again != a {
+		t.Fatalf("expected identical counters for identical salts: %d != %d", again, a)
+	}
+
+	if unsalted := counter(); unsalted == a || unsalted == b {
+		t.Fatalf("expected salted counters to differ from unsalted counter %d", unsalted)
+	}
+}
+
+func TestNewWithSeed(t *testing.T) {
+	original := New()
+	seed := original.Seed()
+
+	// The returned seed is a copy.
+	seed[0] ^= 0xff
+	if original.Seed() == seed {
+		t.Fatal("Seed() returned a reference to the generator's seed")
+	}
+	seed[0] ^= 0xff
+
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clockA := newManualClock(start)
+	clockB := newManualClock(start)
+
+	a := NewWithSeed(seed, WithClock(clockA))
+	b := NewWithSeed(seed, WithClock(clockB))
+
+	if a.Seed() != original.Seed() {
+		t.Fatal("NewWithSeed() did not keep the supplied seed")
+	}
+
+	for i := 0; i < 1000; i++ {
+		if i%100 == 0 {
+			clockA.Add(time.Millisecond)
+			clockB.Add(time.Millisecond)
+		}
+		idA, idB := a.Generate(), b.Generate()
+		if idA != idB {
+			t.Fatalf("generators diverged at ID %d: %d != %d", i, idA, idB)
+		}
+	}
+}
+
+func TestSetEntropy(t *testing.T) {
+	gen := New()
+	mask := gen.layout.stepSeedMask()
+
+	entropy := bytes.Repeat([]byte{0x01, 0x02, 0x03, 0x04}, 32)
+	gen.SetEntropy(bytes.NewReader(entropy))
+
+	want := initCounter(gen.seed, nil, bytes.NewReader(entropy), mask, nil)
+	gen.mu.Lock()
+	got := gen.newCounter()
+	gen.mu.Unlock()
+	if got != want {
+		t.Fatalf("counter seed after SetEntropy = %d, want %d", got, want)
+	}
+
+	// The second 32-byte block of the stream seeds the next counter.
+	want = initCounter(gen.seed, nil, bytes.NewReader(entropy[32:]), mask, nil)
+	gen.mu.Lock()
+	got = gen.newCounter()
+	gen.mu.Unlock()
+	if got != want {
+		t.Fatalf("second counter seed = %d, want %d", got, want)
+	}
+
+	gen.SetEntropy(nil)
+	if gen.entropy != rand.Reader {
+		t.Fatal("SetEntropy(nil) should restore crypto/rand")
+	}
+}
+
 func TestPackageLevelOverrides(t *testing.T) {
 	origEpoch := Epoch
 	t.Cleanup(func() {
@@ -251,6 +493,214 @@ func TestTimebitsClamp(t *testing.T) {
 	}
 }
 
+func TestStrictTimebits(t *testing.T) {
+	origTimebits, origStrict := Timebits, StrictTimebits
+	t.Cleanup(func() {
+		Timebits, StrictTimebits = origTimebits, origStrict
+	})
+
+	// Lenient by default: out-of-range values are clamped.
+	Timebits = 50
+	gen, err := NewGenerator()
+	if err != nil {
+		t.Fatalf("lenient NewGenerator() failed: %v", err)
+	}
+	if got := gen.Layout().timebits(); got != maxTimebits {
+		t.Fatalf("expected clamp to %d, got %d", maxTimebits, got)
+	}
+
+	StrictTimebits = true
+	for _, bits := range []int{50, 39, 0} {
+		Timebits = bits
+		if _, err := NewGenerator(); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("strict NewGenerator() with Timebits=%d error = %v, want ErrOutOfRange", bits, err)
+		}
+	}
+
+	Timebits = 44
+	if _, err := NewGenerator(); err != nil {
+		t.Fatalf("strict NewGenerator() with valid Timebits failed: %v", err)
+	}
+}
+
+func TestGenerateForNode(t *testing.T) {
+	origNodebits := Nodebits
+	t.Cleanup(func() {
+		Nodebits = origNodebits
+	})
+
+	Nodebits = 4
+
+	gen := New(WithNode(3))
+
+	id := gen.Generate()
+	if id.Node() != 3 {
+		t.Fatalf("expected node 3, got %d", id.Node())
+	}
+
+	override, err := gen.GenerateForNode(9)
+	if err != nil {
+		t.Fatalf("GenerateForNode() failed: %v", err)
+	}
+	if override.Node() != 9 {
+		t.Fatalf("expected overridden node 9, got %d", override.Node())
+	}
+	if time.Since(override.Time()) > time.Second {
+		t.Fatalf("ID time not near now: %v", override.Time())
+	}
+
+	if _, err := gen.GenerateForNode(16); err == nil {
+		t.Fatal("expected error for node exceeding node bits")
+	}
+
+	if _, err := NewGenerator(WithNode(16)); err == nil {
+		t.Fatal("expected error for generator node exceeding node bits")
+	}
+}
+
+func TestGenerateForNodeWithoutNodeBits(t *testing.T) {
+	gen := New()
+
+	if _, err := gen.GenerateForNode(1); !errors.Is(err, ErrNoNodeBits) {
+		t.Fatalf("expected ErrNoNodeBits, got %v", err)
+	}
+}
+
+func TestGenerateAfter(t *testing.T) {
+	origTimebits := Timebits
+	t.Cleanup(func() {
+		Timebits = origTimebits
+	})
+
+	// The smallest step space makes the borrowed millisecond roll over.
+	Timebits = maxTimebits
+	gen := New()
+
+	future := time.Now().Add(time.Hour)
+	min := ID((future.UnixMilli()-Epoch)<<currentTimeShift() | int64(currentStepMask()-10))
+
+	id := gen.GenerateAfter(min)
+	if id <= min {
+		t.Fatalf("GenerateAfter() = %d, want > %d", id, min)
+	}
+
+	// Subsequent IDs keep increasing past the borrowed millisecond without
+	// waiting an hour for the clock.
+	prev := id
+	for i := 0; i < 100_000; i++ {
+		next := gen.Generate()
+		if next <= prev {
+			t.Fatalf("IDs not in order after GenerateAfter: %d <= %d", next, prev)
+		}
+		prev = next
+	}
+
+	if d := gen.MaxBackwardDrift(); d != 0 {
+		t.Fatalf("borrowing ahead of the clock reported drift %v", d)
+	}
+
+	past := gen.GenerateAfter(ParseInt64(1))
+	if past <= prev {
+		t.Fatalf("GenerateAfter() with an old minimum = %d, want > %d", past, prev)
+	}
+}
+
+func TestGenerateAfterMaxID(t *testing.T) {
+	gen := New()
+	l := gen.Layout()
+	prev := gen.Generate()
+
+	func() {
+		defer func() {
+			err, _ := recover().(error)
+			if !errors.Is(err, ErrOutOfRange) {
+				t.Fatalf("GenerateAfter(MaxID) panicked with %v, want ErrOutOfRange", err)
+			}
+		}()
+		id := gen.GenerateAfter(MaxID(l))
+		t.Fatalf("GenerateAfter(MaxID) = %d, want a panic", id)
+	}()
+
+	next := gen.Generate()
+	if next <= prev || l.millis(next) == 0 {
+		t.Fatalf("Generate() after the failed GenerateAfter = %d (millisecond %d), want above %d", next, l.millis(next), prev)
+	}
+}
+
+func TestGenerateAfterNodes(t *testing.T) {
+	origNodebits := Nodebits
+	t.Cleanup(func() {
+		Nodebits = origNodebits
+	})
+
+	Nodebits = 4
+	future := time.Now().Add(time.Minute).UnixMilli() - Epoch
+	l := DefaultLayout()
+
+	for _, node := range []uint16{2, 7, 12} {
+		gen := New(WithNode(node))
+		min := l.compose(future, 7, 100)
+
+		id := gen.GenerateAfter(min)
+		if id <= min {
+			t.Fatalf("node %d: GenerateAfter() = %d, want > %d", node, id, min)
+		}
+		if id.Node() != uint64(node) {
+			t.Fatalf("node %d: GenerateAfter() stamped node %d", node, id.Node())
+		}
+	}
+}
+
+func TestRemainingThisMillis(t *testing.T) {
+	origTimebits := Timebits
+	t.Cleanup(func() {
+		Timebits = origTimebits
+	})
+
+	Timebits = maxTimebits
+	clock := newManualClock(time.Now())
+	gen := New(WithClock(clock))
+
+	_ = gen.Generate()
+	prev := gen.RemainingThisMillis()
+	if prev > currentStepMask() {
+		t.Fatalf("RemainingThisMillis() = %d exceeds the step mask", prev)
+	}
+
+	// The clock is frozen, so every ID consumes the current millisecond.
+	for prev > 0 {
+		_ = gen.Generate()
+		remaining := gen.RemainingThisMillis()
+		if remaining != prev-1 {
+			t.Fatalf("RemainingThisMillis() = %d, want %d", remaining, prev-1)
+		}
+		prev = remaining
+	}
+}
+
+func TestClose(t *testing.T) {
+	gen := New()
+
+	if _, err := gen.GenerateSafe(); err != nil {
+		t.Fatalf("GenerateSafe() failed: %v", err)
+	}
+
+	if err := gen.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	if _, err := gen.GenerateSafe(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("GenerateSafe() after Close error = %v, want ErrClosed", err)
+	}
+
+	defer func() {
+		if r := recover(); r != ErrClosed {
+			t.Fatalf("Generate() after Close panicked with %v, want ErrClosed", r)
+		}
+	}()
+	_ = gen.Generate()
+}
+
 func TestConcurrency(t *testing.T) {
 	gen := New()
 
@@ -316,6 +766,201 @@ func TestBitAllocation(t *testing.T) {
 	}
 }
 
+// flakyReader fails its first fails reads, then reads from r.
+type flakyReader struct {
+	fails int
+	r     io.Reader
+}
+
+func (f *flakyReader) Read(p []byte) (int, error) {
+	if f.fails > 0 {
+		f.fails--
+		return 0, errors.New("entropy temporarily unavailable")
+	}
+	return f.r.Read(p)
+}
+
+func TestEntropyRetry(t *testing.T) {
+	gen := New()
+	mask := gen.layout.stepSeedMask()
+	entropy := bytes.Repeat([]byte{0x05, 0x06, 0x07, 0x08}, 8)
+
+	gen.SetEntropy(&flakyReader{fails: 2, r: bytes.NewReader(entropy)})
+	gen.mu.Lock()
+	got := gen.newCounter()
+	gen.mu.Unlock()
+
+	if want := initCounter(gen.seed, nil, bytes.NewReader(entropy), mask, nil); got != want {
+		t.Fatalf("counter seed after retries = %d, want %d", got, want)
+	}
+	if n := gen.RandFailures(); n != 2 {
+		t.Fatalf("RandFailures() = %d, want 2", n)
+	}
+
+	// A source that keeps failing is given up on after entropyAttempts.
+	gen.SetEntropy(&flakyReader{fails: 100, r: bytes.NewReader(entropy)})
+	gen.Generate()
+	gen.mu.Lock()
+	_ = gen.newCounter()
+	gen.mu.Unlock()
+	if n := gen.RandFailures(); n < 2+entropyAttempts {
+		t.Fatalf("RandFailures() = %d, want at least %d", n, 2+entropyAttempts)
+	}
+}
+
+func TestWithFastReseed(t *testing.T) {
+	var seed [32]byte
+	seed[0] = 7
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	a := NewWithSeed(seed, WithClock(newManualClock(start)), WithFastReseed())
+	b := NewWithSeed(seed, WithClock(newManualClock(start)), WithFastReseed())
+
+	seen := make(map[ID]bool)
+	var prev ID
+	for i := 0; i < 1000; i++ {
+		a.clock.(*manualClock).Add(time.Millisecond)
+		b.clock.(*manualClock).Add(time.Millisecond)
+
+		id := a.Generate()
+		if other := b.Generate(); other != id {
+			t.Fatalf("fast reseed not reproducible from seed: %d != %d", id, other)
+		}
+		if seen[id] || id <= prev {
+			t.Fatalf("ID %d duplicate or out of order (prev %d)", id, prev)
+		}
+		seen[id] = true
+		prev = id
+
+		if step := id.Uint64() & currentStepMask(); step > currentStepSeedMask() {
+			t.Fatalf("fast seed %d outside lower half of step space", step)
+		}
+	}
+}
+
+func TestWithStrictEpoch(t *testing.T) {
+	origEpoch := Epoch
+	t.Cleanup(func() {
+		Epoch = origEpoch
+	})
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newManualClock(now)
+	Epoch = now.Add(24 * time.Hour).UnixMilli()
+
+	// Without the option the timestamp is clamped to zero.
+	lenient := New(WithClock(clock))
+	if id, err := lenient.GenerateSafe(); err != nil || lenient.Layout().Time(id).UnixMilli() != Epoch {
+		t.Fatalf("lenient GenerateSafe() = %d, %v; want an ID at the epoch", id, err)
+	}
+
+	strict := New(WithClock(clock), WithStrictEpoch())
+	if _, err := strict.GenerateSafe(); !errors.Is(err, ErrBeforeEpoch) {
+		t.Fatalf("GenerateSafe() before epoch error = %v, want ErrBeforeEpoch", err)
+	}
+
+	clock.Add(25 * time.Hour)
+	if _, err := strict.GenerateSafe(); err != nil {
+		t.Fatalf("GenerateSafe() after epoch failed: %v", err)
+	}
+}
+
+func TestGenerateEncoded(t *testing.T) {
+	gen := New()
+
+	s := gen.GenerateString()
+	id, err := ParseString(s)
+	if err != nil || id <= 0 {
+		t.Fatalf("ParseString(GenerateString()) = %d, %v", id, err)
+	}
+
+	h := gen.GenerateHex()
+	next, err := ParseHex(h)
+	if err != nil || next <= id {
+		t.Fatalf("ParseHex(GenerateHex()) = %d, %v; want an ID after %d", next, err, id)
+	}
+
+	i := gen.GenerateInt64()
+	if last := ParseInt64(i); last <= next {
+		t.Fatalf("GenerateInt64() = %d, want an ID after %d", i, next)
+	}
+}
+
+func TestTimeLocation(t *testing.T) {
+	origLocation := TimeLocation
+	t.Cleanup(func() {
+		TimeLocation = origLocation
+	})
+
+	id := New().Generate()
+	if loc := id.Time().Location(); loc != time.UTC {
+		t.Fatalf("default Time() location = %v, want UTC", loc)
+	}
+
+	loc := time.FixedZone("UTC-5", -5*3600)
+	TimeLocation = loc
+	if got := id.Time().Location(); got != loc {
+		t.Fatalf("Time() location = %v, want %v", got, loc)
+	}
+	if !id.Time().Equal(DefaultLayout().Time(id)) {
+		t.Fatal("TimeLocation must not change the instant")
+	}
+
+	TimeLocation = nil
+	if got := id.Time().Location(); got != time.UTC {
+		t.Fatalf("Time() location with nil TimeLocation = %v, want UTC", got)
+	}
+}
+
+func TestGenerateWithForms(t *testing.T) {
+	gen := New()
+	for i := 0; i < 100; i++ {
+		id, s, n := gen.GenerateWithForms()
+		if s != id.Base32() {
+			t.Fatalf("base32 form %q, want %q", s, id.Base32())
+		}
+		if n != id.Int64() {
+			t.Fatalf("int64 form %d, want %d", n, id.Int64())
+		}
+	}
+
+	for _, v := range []ID{0, 1, math.MaxInt64, ID(-1)} {
+		var b [base32Len]byte
+		encodeBase32(&b, v.Uint64())
+		if string(b[:]) != v.Base32() {
+			t.Errorf("encodeBase32(%d) = %q, want %q", v, b, v.Base32())
+		}
+	}
+}
+
+func TestWarmup(t *testing.T) {
+	clock := newManualClock(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	gen := New(WithClock(clock))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			gen.Warmup()
+		}()
+	}
+	wg.Wait()
+
+	first := testing.AllocsPerRun(1, func() { gen.Generate() })
+	steady := testing.AllocsPerRun(100, func() { gen.Generate() })
+	if first > steady {
+		t.Fatalf("first Generate after Warmup allocated %v times, steady state %v", first, steady)
+	}
+
+	// Further calls are no-ops and do not consume IDs.
+	before := gen.RemainingThisMillis()
+	gen.Warmup()
+	if after := gen.RemainingThisMillis(); after != before {
+		t.Fatalf("repeated Warmup consumed IDs: remaining %d -> %d", before, after)
+	}
+}
+
 func BenchmarkGenerate(b *testing.B) {
 	gen := New()
 
@@ -334,3 +979,132 @@ func BenchmarkGenerateParallel(b *testing.B) {
 		}
 	})
 }
+
+func BenchmarkNewCounter(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		opts []Option
+	}{
+		{"crypto", nil},
+		{"fast", []Option{WithFastReseed()}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			gen := New(bc.opts...)
+			gen.mu.Lock()
+			defer gen.mu.Unlock()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = gen.newCounter()
+			}
+		})
+	}
+}
+
+func BenchmarkGenerateWithForms(b *testing.B) {
+	gen := New()
+
+	b.Run("combined", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _, _ = gen.GenerateWithForms()
+		}
+	})
+	b.Run("separate", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			id := gen.Generate()
+			_, _ = id.Base32(), id.Int64()
+		}
+	})
+}
+
+func TestRemainingThisSecond(t *testing.T) {
+	boundary := time.Date(2024, 6, 1, 12, 0, 1, 0, time.UTC)
+	clock := newManualClock(boundary.Add(-10 * time.Millisecond))
+	gen := New(WithClock(clock))
+	l := gen.Layout()
+	fresh := l.stepMask() - l.stepSeedMask()
+
+	gen.Generate()
+	far := gen.RemainingThisSecond()
+	if want := gen.RemainingThisMillis() + 9*fresh; far != want {
+		t.Fatalf("RemainingThisSecond() = %d, want %d", far, want)
+	}
+
+	clock.Set(boundary.Add(-2 * time.Millisecond))
+	gen.Generate()
+	near := gen.RemainingThisSecond()
+	if near >= far {
+		t.Fatalf("RemainingThisSecond() = %d near the boundary, want less than %d", near, far)
+	}
+	if want := gen.RemainingThisMillis() + fresh; near != want {
+		t.Fatalf("RemainingThisSecond() = %d, want %d", near, want)
+	}
+}
+
+func TestSingleStepBit(t *testing.T) {
+	origTimebits, origNodebits := Timebits, Nodebits
+	t.Cleanup(func() {
+		Timebits, Nodebits = origTimebits, origNodebits
+	})
+	Timebits, Nodebits = maxTimebits, 14 // 63 - 48 - 14 = 1 step bit
+
+	gen := New(WithNode(0x2aaa))
+	l := gen.Layout()
+	if l.stepBits() != 1 || l.stepSeedMask() != 0 {
+		t.Fatalf("stepBits, stepSeedMask = %d, %d, want 1, 0", l.stepBits(), l.stepSeedMask())
+	}
+
+	const goroutines, perG = 4, 25
+	results := make(chan ID, goroutines*perG)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perG; j++ {
+				id, err := gen.GenerateSafe()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				results <- id
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("generation made no progress with a single step bit")
+	}
+	close(results)
+
+	perMillis := make(map[int64]int)
+	seen := make(map[ID]bool)
+	for id := range results {
+		if seen[id] {
+			t.Fatalf("duplicate ID %d", id)
+		}
+		seen[id] = true
+		if l.Node(id) != 0x2aaa {
+			t.Fatalf("ID %d carries node %#x", id, l.Node(id))
+		}
+		perMillis[l.millis(id)]++
+	}
+	if len(seen) != goroutines*perG {
+		t.Fatalf("generated %d IDs, want %d", len(seen), goroutines*perG)
+	}
+	for millis, n := range perMillis {
+		if n > 2 {
+			t.Fatalf("%d IDs in millisecond %d, want at most 2", n, millis)
+		}
+	}
+}
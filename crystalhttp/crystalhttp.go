@@ -0,0 +1,39 @@
+// Package crystalhttp provides HTTP middleware that tags every request with
+// a crystal request ID.
+package crystalhttp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kwo/crystal"
+)
+
+// Header is the response header the middleware sets to the request ID.
+const Header = "X-Request-ID"
+
+// contextKey is the type of the request-ID context key, unexported so that
+// no other package can collide with it.
+type contextKey struct{}
+
+// Middleware returns a handler that gives every request a fresh ID from
+// gen.RequestID, stores it in the request context (see FromContext) and sets
+// it as the X-Request-ID response header before calling next.
+func Middleware(gen *crystal.Generator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := gen.RequestID()
+		w.Header().Set(Header, id)
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+	})
+}
+
+// NewContext returns a copy of ctx carrying the request ID id.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID stored in ctx by Middleware, if any.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok
+}
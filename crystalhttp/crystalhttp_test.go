@@ -0,0 +1,45 @@
+package crystalhttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kwo/crystal"
+)
+
+func TestMiddleware(t *testing.T) {
+	var seen string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := FromContext(r.Context())
+		if !ok {
+			t.Error("request context carries no request ID")
+		}
+		seen = id
+	})
+	handler := Middleware(crystal.New(), next)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	header := rec.Header().Get(Header)
+	if header == "" || header != seen {
+		t.Fatalf("%s = %q, context ID = %q, want equal and non-empty", Header, header, seen)
+	}
+	if _, err := crystal.ParseBase32(header); err != nil {
+		t.Fatalf("request ID %q does not parse: %v", header, err)
+	}
+
+	rec2 := httptest.NewRecorder()
+	handler.ServeHTTP(rec2, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec2.Header().Get(Header) == header {
+		t.Fatal("two requests got the same request ID")
+	}
+}
+
+func TestFromContextMissing(t *testing.T) {
+	if id, ok := FromContext(context.Background()); ok || id != "" {
+		t.Fatalf("FromContext(empty) = %q, %v, want \"\", false", id, ok)
+	}
+}
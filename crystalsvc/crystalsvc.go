@@ -0,0 +1,68 @@
+// Package crystalsvc provides the logic of a small ID service backed by a
+// crystal generator. It has no transport of its own: wrap a Handler in a gRPC
+// or HTTP server by calling it through the Service interface.
+package crystalsvc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kwo/crystal"
+)
+
+// MaxBatch is the largest batch NextBatch issues in one call, so that a
+// single request cannot monopolize the generator.
+const MaxBatch = 10000
+
+// ErrBatchSize is returned by NextBatch for a batch size below 1 or above
+// MaxBatch.
+var ErrBatchSize = errors.New("crystalsvc: batch size out of range")
+
+// Service is the interface a transport exposes. IDs are plain int64 values so
+// they map directly onto protobuf int64 and JSON numbers.
+type Service interface {
+	// Next returns a new unique ID.
+	Next() (int64, error)
+	// NextBatch returns n new unique IDs in ascending order.
+	NextBatch(n int) ([]int64, error)
+}
+
+// Handler implements Service on top of a Generator.
+type Handler struct {
+	gen *crystal.Generator
+}
+
+var _ Service = (*Handler)(nil)
+
+// New returns a Handler issuing IDs from gen.
+func New(gen *crystal.Generator) *Handler {
+	return &Handler{gen: gen}
+}
+
+// Next returns a new unique ID, or the generator's error (such as
+// crystal.ErrClosed) if it cannot issue one.
+func (h *Handler) Next() (int64, error) {
+	id, err := h.gen.GenerateSafe()
+	if err != nil {
+		return 0, err
+	}
+	return id.Int64(), nil
+}
+
+// NextBatch returns n new unique IDs in ascending order. It returns an error
+// wrapping ErrBatchSize unless 1 <= n <= MaxBatch, and the generator's error
+// if it fails part way, in which case no IDs are returned.
+func (h *Handler) NextBatch(n int) ([]int64, error) {
+	if n < 1 || n > MaxBatch {
+		return nil, fmt.Errorf("%w: %d", ErrBatchSize, n)
+	}
+	ids := make([]int64, n)
+	for i := range ids {
+		id, err := h.gen.GenerateSafe()
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id.Int64()
+	}
+	return ids, nil
+}
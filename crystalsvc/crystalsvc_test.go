@@ -0,0 +1,69 @@
+package crystalsvc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kwo/crystal"
+)
+
+func TestNext(t *testing.T) {
+	var svc Service = New(crystal.New())
+
+	prev := int64(0)
+	for i := 0; i < 1000; i++ {
+		id, err := svc.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= prev {
+			t.Fatalf("Next() = %d after %d, want ascending", id, prev)
+		}
+		prev = id
+	}
+}
+
+func TestNextBatch(t *testing.T) {
+	var svc Service = New(crystal.New())
+
+	seen := make(map[int64]bool)
+	prev := int64(0)
+	for round := 0; round < 5; round++ {
+		ids, err := svc.NextBatch(500)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 500 {
+			t.Fatalf("NextBatch(500) returned %d IDs", len(ids))
+		}
+		for _, id := range ids {
+			if seen[id] {
+				t.Fatalf("duplicate ID %d", id)
+			}
+			seen[id] = true
+			if id <= prev {
+				t.Fatalf("ID %d after %d, want ascending", id, prev)
+			}
+			prev = id
+		}
+	}
+
+	for _, n := range []int{0, -1, MaxBatch + 1} {
+		if _, err := svc.NextBatch(n); !errors.Is(err, ErrBatchSize) {
+			t.Errorf("NextBatch(%d) error = %v, want ErrBatchSize", n, err)
+		}
+	}
+}
+
+func TestClosedGenerator(t *testing.T) {
+	gen := crystal.New()
+	svc := New(gen)
+	_ = gen.Close()
+
+	if _, err := svc.Next(); !errors.Is(err, crystal.ErrClosed) {
+		t.Errorf("Next() error = %v, want ErrClosed", err)
+	}
+	if ids, err := svc.NextBatch(3); !errors.Is(err, crystal.ErrClosed) || ids != nil {
+		t.Errorf("NextBatch(3) = %v, %v; want nil, ErrClosed", ids, err)
+	}
+}
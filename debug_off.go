@@ -0,0 +1,9 @@
+//go:build !crystaldebug
+
+package crystal
+
+// debugState is empty unless built with -tags crystaldebug.
+type debugState struct{}
+
+// check is a no-op unless built with -tags crystaldebug.
+func (*debugState) check(ID) {}
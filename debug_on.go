@@ -0,0 +1,22 @@
+//go:build crystaldebug
+
+package crystal
+
+import "fmt"
+
+// debugState tracks the last ID Generate returned so that crystaldebug builds
+// can assert the sequence is strictly increasing.
+type debugState struct {
+	last ID
+	seen bool
+}
+
+// check panics if id does not exceed the previously returned ID. It must be
+// called with the generator lock held.
+func (d *debugState) check(id ID) {
+	if d.seen && id <= d.last {
+		panic(fmt.Sprintf("crystal: Generate returned %d after %d", id, d.last))
+	}
+	d.last = id
+	d.seen = true
+}
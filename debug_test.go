@@ -0,0 +1,71 @@
+//go:build crystaldebug
+
+package crystal
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDebugCheckPanics(t *testing.T) {
+	var d debugState
+	d.check(10)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected check to panic on a non-increasing ID")
+		}
+	}()
+	d.check(10)
+}
+
+func TestDebugConcurrentGeneration(t *testing.T) {
+	origTimebits := Timebits
+	t.Cleanup(func() {
+		Timebits = origTimebits
+	})
+
+	// The smallest step space forces frequent rollovers and reseeds.
+	Timebits = maxTimebits
+
+	start := time.Now()
+	clock := newManualClock(start)
+	gen := New(WithClock(clock))
+
+	const numGoroutines = 16
+	const idsPerGoroutine = 20_000
+
+	var wg sync.WaitGroup
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < idsPerGoroutine; j++ {
+				_ = gen.Generate()
+			}
+		}()
+	}
+
+	// Keep the clock moving, with occasional backward jumps to exercise
+	// clamping, until the workers finish.
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	for tick := 1; ; tick++ {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		if tick%10 == 0 {
+			clock.Add(-3 * time.Millisecond)
+		} else {
+			clock.Add(time.Millisecond)
+		}
+		time.Sleep(50 * time.Microsecond)
+	}
+}
@@ -0,0 +1,34 @@
+package crystal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// DecodeReader reads whitespace-separated base32 tokens from r and yields each
+// parsed ID. Tokens that fail to parse are yielded with a zero ID and an error
+// naming the token, and decoding carries on with the next token; blank lines
+// and runs of whitespace are skipped. A read error from r is yielded last.
+// The input is streamed, so arbitrarily large logs use constant memory.
+func DecodeReader(r io.Reader) iter.Seq2[ID, error] {
+	return func(yield func(ID, error) bool) {
+		sc := bufio.NewScanner(r)
+		sc.Split(bufio.ScanWords)
+
+		for sc.Scan() {
+			tok := sc.Text()
+			id, err := ParseBase32(tok)
+			if err != nil {
+				err = fmt.Errorf("invalid ID %q: %w", tok, err)
+			}
+			if !yield(id, err) {
+				return
+			}
+		}
+		if err := sc.Err(); err != nil {
+			yield(0, err)
+		}
+	}
+}
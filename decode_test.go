@@ -0,0 +1,46 @@
+package crystal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeReader(t *testing.T) {
+	gen := New()
+	a, b, c := gen.Generate(), gen.Generate(), gen.Generate()
+
+	input := a.Base32() + " " + b.Base32() + "\n" +
+		"\n" +
+		"   not-an-id!  \t" + c.Base32() + "\n" +
+		"0123\n"
+
+	var ids []ID
+	var errs []error
+	for id, err := range DecodeReader(strings.NewReader(input)) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	if len(ids) != 3 || ids[0] != a || ids[1] != b || ids[2] != c {
+		t.Fatalf("decoded %v, want [%d %d %d]", ids, a, b, c)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("got %d errors, want 2: %v", len(errs), errs)
+	}
+	if !strings.Contains(errs[0].Error(), "not-an-id!") {
+		t.Errorf("error %q does not name the token", errs[0])
+	}
+
+	// Stopping early must not read further.
+	n := 0
+	for range DecodeReader(strings.NewReader(input)) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Fatalf("iterated %d times after break, want 1", n)
+	}
+}
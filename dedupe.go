@@ -0,0 +1,57 @@
+package crystal
+
+import (
+	"errors"
+	"fmt"
+)
+
+// dedupeAttempts bounds how many IDs Generate draws per call while a Seen set
+// reports them as duplicates.
+const dedupeAttempts = 8
+
+// ErrDuplicate is returned when every ID drawn for a call was reported as
+// already seen by the WithDedupeCheck set.
+var ErrDuplicate = errors.New("crystal: generated IDs reported as duplicates")
+
+// Seen is a set of issued IDs, typically a Bloom filter, consulted by
+// WithDedupeCheck. Contains may report false positives. Both methods are
+// called with the generator lock held, so they should be fast and must not
+// call back into the generator.
+type Seen interface {
+	Contains(id ID) bool
+	Add(id ID)
+}
+
+// WithDedupeCheck makes the generator check every ID it issues against s:
+// an ID s already contains is skipped and the next one drawn, up to a few
+// times before GenerateSafe fails with ErrDuplicate (and Generate panics
+// with it); issued IDs are added to s. The check is defense in depth for
+// critical systems, not a correctness requirement: generated IDs are unique
+// without it, so a hit points at a false positive or at another process
+// sharing the generator's layout, node and seed. GenerateAfter does not
+// consult s.
+func WithDedupeCheck(s Seen) Option {
+	return func(g *Generator) error {
+		if s == nil {
+			return fmt.Errorf("seen set must not be nil")
+		}
+		g.seen = s
+		return nil
+	}
+}
+
+// nextChecked is nextLocked, skipping IDs the WithDedupeCheck set has seen.
+// It must be called with g.mu held.
+func (g *Generator) nextChecked(now int64, node uint64) (ID, error) {
+	if g.seen == nil {
+		return g.nextLocked(now, node), nil
+	}
+	for i := 0; i < dedupeAttempts; i++ {
+		id := g.nextLocked(now, node)
+		if !g.seen.Contains(id) {
+			g.seen.Add(id)
+			return id, nil
+		}
+	}
+	return 0, ErrDuplicate
+}
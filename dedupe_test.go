@@ -0,0 +1,51 @@
+package crystal
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeSeen reports the first hits IDs it is asked about as already seen.
+type fakeSeen struct {
+	hits    int
+	checked int
+	added   []ID
+}
+
+func (s *fakeSeen) Contains(ID) bool {
+	s.checked++
+	return s.checked <= s.hits
+}
+
+func (s *fakeSeen) Add(id ID) {
+	s.added = append(s.added, id)
+}
+
+func TestWithDedupeCheck(t *testing.T) {
+	seen := &fakeSeen{hits: 1}
+	gen := New(WithDedupeCheck(seen))
+
+	id := gen.Generate()
+	if seen.checked != 2 {
+		t.Fatalf("Contains called %d times, want 2 (one retry)", seen.checked)
+	}
+	if len(seen.added) != 1 || seen.added[0] != id {
+		t.Fatalf("added %v, want [%d]", seen.added, id)
+	}
+	if m := gen.MetricsSnapshot(); m.Generated != 2 {
+		t.Fatalf("Generated = %d, want 2 (the skipped ID counts)", m.Generated)
+	}
+	if next := gen.Generate(); next <= id {
+		t.Fatalf("Generate() = %d after %d, want increasing", next, id)
+	}
+}
+
+func TestWithDedupeCheckExhausted(t *testing.T) {
+	gen := New(WithDedupeCheck(&fakeSeen{hits: dedupeAttempts}))
+	if _, err := gen.GenerateSafe(); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("GenerateSafe() error = %v, want ErrDuplicate", err)
+	}
+	if _, err := gen.GenerateSafe(); err != nil {
+		t.Fatalf("GenerateSafe() after exhaustion failed: %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package crystal
+
+import "sync"
+
+// The package default generator behind Generate, and the clock it and other
+// generators without WithClock read.
+//
+//nolint:gochecknoglobals
+var (
+	defaultMu  sync.Mutex
+	defaultGen *Generator
+
+	clockMu      sync.RWMutex
+	packageClock Clock = systemClock{}
+)
+
+// Generate creates a unique ID with the package default generator, which is
+// created on first use from the package-level configuration. It panics if
+// that configuration is invalid (see New).
+func Generate() ID {
+	return defaultGenerator().Generate()
+}
+
+// defaultGenerator returns the package default generator, creating it if
+// needed.
+func defaultGenerator() *Generator {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	if defaultGen == nil {
+		defaultGen = New()
+	}
+	return defaultGen
+}
+
+// SetClock makes the package default generator and every generator created
+// afterwards without WithClock read the time from c, e.g. to control time in
+// tests that call Generate. The default generator is replaced by a fresh one,
+// so IDs from before and after the switch are not ordered with respect to
+// each other. A nil c restores the system clock.
+func SetClock(c Clock) {
+	if c == nil {
+		c = systemClock{}
+	}
+
+	clockMu.Lock()
+	packageClock = c
+	clockMu.Unlock()
+
+	defaultMu.Lock()
+	defaultGen = nil
+	defaultMu.Unlock()
+}
+
+// ResetClock undoes SetClock, returning to the system clock.
+func ResetClock() {
+	SetClock(nil)
+}
+
+// currentClock returns the clock set with SetClock.
+func currentClock() Clock {
+	clockMu.RLock()
+	defer clockMu.RUnlock()
+
+	return packageClock
+}
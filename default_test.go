@@ -0,0 +1,36 @@
+package crystal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPackageGenerate(t *testing.T) {
+	a, b := Generate(), Generate()
+	if b <= a {
+		t.Fatalf("Generate() = %d after %d, want increasing", b, a)
+	}
+}
+
+func TestSetClock(t *testing.T) {
+	t.Cleanup(ResetClock)
+
+	fake := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	SetClock(newManualClock(fake))
+
+	if got := Generate().Time(); !got.Equal(fake) {
+		t.Fatalf("Generate().Time() = %v, want %v", got, fake)
+	}
+	if got := New().Generate().Time(); !got.Equal(fake) {
+		t.Fatalf("New().Generate().Time() = %v, want %v", got, fake)
+	}
+	own := newManualClock(fake.Add(time.Hour))
+	if got := New(WithClock(own)).Generate().Time(); !got.Equal(fake.Add(time.Hour)) {
+		t.Fatalf("WithClock generator Time() = %v, want %v", got, fake.Add(time.Hour))
+	}
+
+	ResetClock()
+	if got := Generate().Time(); time.Since(got) > time.Minute {
+		t.Fatalf("Generate().Time() = %v after ResetClock, want about now", got)
+	}
+}
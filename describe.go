@@ -0,0 +1,41 @@
+package crystal
+
+import "time"
+
+// Description is a plain, JSON-friendly view of an ID's encodings and
+// components under a particular layout, e.g. for debugging endpoints.
+type Description struct {
+	Int64  int64     `json:"int64"`
+	Base32 string    `json:"base32"`
+	Hex    string    `json:"hex"`
+	Time   time.Time `json:"time"`
+	Step   uint64    `json:"step"`
+	// Node is nil when the layout has no node bits.
+	Node *uint64 `json:"node,omitempty"`
+}
+
+// Describe decodes id under l into a Description. Its time is reported in
+// UTC.
+func (id ID) Describe(l Layout) Description {
+	d := Description{
+		Int64:  id.Int64(),
+		Base32: id.Base32(),
+		Hex:    id.Hex(),
+		Time:   l.Time(id).UTC(),
+		Step:   l.Step(id),
+	}
+	if l.nodebits() > 0 {
+		node := l.Node(id)
+		d.Node = &node
+	}
+	return d
+}
+
+// LogFields returns id's timestamp (Unix milliseconds), step and node under l
+// as plain numbers, for emitting as structured log fields without building a
+// Description. The node is 0 when the layout has no node bits. The kind tag
+// is not included, and for a Microsecond layout the timestamp is truncated
+// to the millisecond.
+func (id ID) LogFields(l Layout) (tsMillis int64, step uint64, node uint64) {
+	return l.Time(id).UnixMilli(), l.Step(id), l.Node(id)
+}
@@ -0,0 +1,80 @@
+package crystal
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDescribe(t *testing.T) {
+	l := Layout{
+		Epoch:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli(),
+		Timebits: 42,
+		Nodebits: 5,
+	}
+	ts := time.Date(2024, 6, 1, 12, 0, 0, 250_000_000, time.UTC)
+	id := l.compose(ts.UnixMilli()-l.Epoch, 7, 99)
+
+	data, err := json.Marshal(id.Describe(l))
+	if err != nil {
+		t.Fatalf("json.Marshal() failed: %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal() failed: %v", err)
+	}
+
+	want := map[string]any{
+		"int64":  float64(id.Int64()),
+		"base32": id.Base32(),
+		"hex":    id.Hex(),
+		"time":   "2024-06-01T12:00:00.25Z",
+		"step":   float64(99),
+		"node":   float64(7),
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k], v)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d fields, want %d: %s", len(got), len(want), data)
+	}
+
+	l.Nodebits = 0
+	if d := id.Describe(l); d.Node != nil {
+		t.Errorf("Node = %d, want nil without node bits", *d.Node)
+	}
+}
+
+func TestLogFields(t *testing.T) {
+	l := Layout{
+		Epoch:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli(),
+		Timebits: 42,
+		Nodebits: 5,
+	}
+	ts := time.Date(2024, 6, 1, 12, 0, 0, 250_000_000, time.UTC)
+	id := l.compose(ts.UnixMilli()-l.Epoch, 7, 99)
+
+	tsMillis, step, node := id.LogFields(l)
+	if tsMillis != ts.UnixMilli() || step != 99 || node != 7 {
+		t.Fatalf("LogFields() = %d, %d, %d; want %d, 99, 7", tsMillis, step, node, ts.UnixMilli())
+	}
+	if got := l.compose(tsMillis-l.Epoch, node, step); got != id {
+		t.Fatalf("recombined ID = %d, want %d", got, id)
+	}
+
+	gen := New()
+	gl := gen.Layout()
+	for i := 0; i < 100; i++ {
+		id := gen.Generate()
+		tsMillis, step, node := id.LogFields(gl)
+		if node != 0 {
+			t.Fatalf("node = %d without node bits", node)
+		}
+		if got := gl.compose(gl.ticksSince(time.UnixMilli(tsMillis)), node, step); got != id {
+			t.Fatalf("recombined ID = %d, want %d", got, id)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package crystal
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Format identifies the textual encoding an ID was parsed from.
+type Format int
+
+// Formats recognized by ParseDetect.
+const (
+	FormatBase32 Format = iota + 1
+	FormatHex
+	FormatDecimal
+	FormatBase62
+)
+
+// String returns the lowercase name of the format.
+func (f Format) String() string {
+	switch f {
+	case FormatBase32:
+		return "base32"
+	case FormatHex:
+		return "hex"
+	case FormatDecimal:
+		return "decimal"
+	case FormatBase62:
+		return "base62"
+	default:
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+}
+
+// Encode returns id in the format f, so a parsed ID can be re-emitted the way
+// it arrived. Unknown formats fall back to base32.
+func (id ID) Encode(f Format) string {
+	switch f {
+	case FormatHex:
+		return id.Hex()
+	case FormatDecimal:
+		return strconv.FormatInt(id.Int64(), 10)
+	case FormatBase62:
+		return id.Base62()
+	default:
+		return id.Base32()
+	}
+}
+
+// ParseAny parses s in whichever format it appears to use; see ParseDetect.
+func ParseAny(s string) (ID, error) {
+	id, _, err := ParseDetect(s)
+	return id, err
+}
+
+// ParseDetect parses s and reports the format it was read as. Formats are
+// tried in this order, the first match winning:
+//
+//   - 13 characters that decode as base32 (the String form);
+//   - 16 hexadecimal characters;
+//   - a run of ASCII digits, read as a decimal int64;
+//   - 11 base62 characters.
+//
+// The fixed-width forms win at their own lengths, as in UnmarshalJSON, so
+// that every Base32 and Hex string round-trips even when it consists solely
+// of digits. A 13- or 16-digit decimal is therefore read as base32 or hex;
+// parse such input with strconv.ParseInt instead. Decimal IDs issued today
+// have 18 or 19 digits, and an 11-digit string is read as decimal, not
+// base62.
+func ParseDetect(s string) (ID, Format, error) {
+	if len(s) == base32Len {
+		if id, err := ParseBase32(s); err == nil {
+			return id, FormatBase32, nil
+		}
+	}
+	if len(s) == 16 {
+		if id, err := ParseHex(s); err == nil {
+			return id, FormatHex, nil
+		}
+	}
+	if isDecimal(s) {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid decimal ID %s: %w", s, err)
+		}
+		return ID(i), FormatDecimal, nil
+	}
+	if len(s) == base62Len {
+		if id, err := ParseBase62(s); err == nil {
+			return id, FormatBase62, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("unrecognized ID format: %q", s)
+}
@@ -0,0 +1,60 @@
+package crystal
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestParseDetect(t *testing.T) {
+	id := ID(237755712226918401)
+
+	tests := []struct {
+		in   string
+		want Format
+	}{
+		{id.Base32(), FormatBase32},
+		{id.Hex(), FormatHex},
+		{strconv.FormatInt(id.Int64(), 10), FormatDecimal},
+		{id.Base62(), FormatBase62},
+	}
+	for _, tt := range tests {
+		got, format, err := ParseDetect(tt.in)
+		if err != nil {
+			t.Fatalf("ParseDetect(%q) failed: %v", tt.in, err)
+		}
+		if got != id || format != tt.want {
+			t.Errorf("ParseDetect(%q) = %d, %s, want %d, %s", tt.in, got, format, id, tt.want)
+		}
+		if enc := got.Encode(format); enc != tt.in {
+			t.Errorf("Encode(%s) = %q, want %q", format, enc, tt.in)
+		}
+	}
+
+	// Fixed-width forms that happen to be all digits still round-trip.
+	for _, tt := range []struct {
+		id     ID
+		format Format
+	}{
+		{ID(0x0189012345678901), FormatHex},
+		{ID(1), FormatBase32},
+		{ID(12345678901), FormatDecimal},
+	} {
+		in := tt.id.Encode(tt.format)
+		got, format, err := ParseDetect(in)
+		if err != nil || got != tt.id || format != tt.format {
+			t.Errorf("ParseDetect(%q) = %d, %s, %v; want %d, %s", in, got, format, err, tt.id, tt.format)
+		}
+	}
+
+	if parsed, err := ParseAny(id.Hex()); err != nil || parsed != id {
+		t.Errorf("ParseAny(hex) = %d, %v, want %d", parsed, err, id)
+	}
+}
+
+func TestParseDetectInvalid(t *testing.T) {
+	for _, in := range []string{"", "not-an-id", "99999999999999999999", "zzzzzzzzzzzzzzzzzz"} {
+		if _, _, err := ParseDetect(in); err == nil {
+			t.Errorf("ParseDetect(%q) succeeded, want error", in)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package crystal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeterministicID(t *testing.T) {
+	l := DefaultLayout()
+	ns := [16]byte{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+
+	a := DeterministicID(ns, "customer-42", l)
+	if b := DeterministicID(ns, "customer-42", l); b != a {
+		t.Fatalf("same inputs gave %d and %d", a, b)
+	}
+	if a <= 0 {
+		t.Fatalf("DeterministicID() = %d, want positive", a)
+	}
+
+	if b := DeterministicID(ns, "customer-43", l); b == a {
+		t.Fatal("different names gave the same ID")
+	}
+	other := ns
+	other[0] ^= 1
+	if b := DeterministicID(other, "customer-42", l); b == a {
+		t.Fatal("different namespaces gave the same ID")
+	}
+
+	// The timestamp stays in the first half of the layout's range.
+	half := time.UnixMilli(l.Epoch + int64(1)<<uint(l.timebits()-1))
+	for _, name := range []string{"", "a", "b", "customer-42", "a much longer external key"} {
+		id := DeterministicID(ns, name, l)
+		if ts := l.Time(id); ts.Before(time.UnixMilli(l.Epoch)) || !ts.Before(half) {
+			t.Errorf("DeterministicID(%q) time %v outside [epoch, %v)", name, ts, half)
+		}
+	}
+}
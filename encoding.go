@@ -0,0 +1,155 @@
+package crystal
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// base62Alphabet is in ASCII order so fixed-width encodings sort like the IDs.
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// base62Len is the number of base62 characters needed for any 63-bit value.
+const base62Len = 11
+
+// Base62 returns the fixed-width (11 character) base62 string representation.
+// Fixed width keeps string order consistent with numeric order.
+func (id ID) Base62() string {
+	var b [base62Len]byte
+	return string(id.AppendBase62(b[:0]))
+}
+
+// ParseBase62 parses a base62 string into an ID. Shorter inputs are accepted
+// as if left-padded with zeros.
+func ParseBase62(s string) (ID, error) {
+	if s == "" || len(s) > base62Len {
+		return 0, fmt.Errorf("invalid base62 length: %d", len(s))
+	}
+
+	var v uint64
+	for i := 0; i < len(s); i++ {
+		d := base62Digit(s[i])
+		if d < 0 {
+			return 0, fmt.Errorf("invalid base62 character %q at %d", s[i], i)
+		}
+		if v > (math.MaxInt64-uint64(d))/62 {
+			return 0, fmt.Errorf("base62 value out of range: %s", s)
+		}
+		v = v*62 + uint64(d)
+	}
+	return ID(v), nil
+}
+
+// base36Alphabet is strconv's lowercase base-36 alphabet.
+const base36Alphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// base36Len is the number of base36 characters needed for any 63-bit value.
+const base36Len = 13
+
+// Base36 returns the fixed-width (13 character) lowercase base36 string
+// representation, for legacy systems that only accept base36 tokens. Fixed
+// width keeps string order consistent with numeric order.
+func (id ID) Base36() string {
+	var b [base36Len]byte
+	return string(id.AppendBase36(b[:0]))
+}
+
+// ParseBase36 parses a base36 string into an ID. Letters may be upper or
+// lower case, and shorter inputs are accepted as if left-padded with zeros.
+func ParseBase36(s string) (ID, error) {
+	if s == "" || len(s) > base36Len {
+		return 0, fmt.Errorf("invalid base36 length: %d", len(s))
+	}
+	if s[0] == '+' || s[0] == '-' {
+		return 0, fmt.Errorf("invalid base36 string: %s", s)
+	}
+	v, err := strconv.ParseInt(s, 36, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid base36 string: %s: %w", s, err)
+	}
+	return ID(v), nil
+}
+
+// base62Digit returns the value of c in base62Alphabet, or -1.
+func base62Digit(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 10
+	case c >= 'a' && c <= 'z':
+		return int(c-'a') + 36
+	default:
+		return -1
+	}
+}
+
+// Array returns the ID as 8 big-endian bytes. Being a value rather than a
+// slice, it does not allocate and is comparable, which makes it the canonical
+// fixed-size key form, e.g. for maps or caches keyed by raw bytes. Arrays sort
+// bytewise in the same order as the IDs.
+func (id ID) Array() [8]byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], id.Uint64())
+	return b
+}
+
+// FromBytes is the inverse of Array.
+func FromBytes(b [8]byte) ID {
+	return ID(binary.BigEndian.Uint64(b[:])) //nolint:gosec
+}
+
+// base32Alphabet is the lowercase Crockford alphabet used by base32Encoding.
+const base32Alphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+
+// base32Len is the number of base32 characters for 8 bytes without padding.
+const base32Len = 13
+
+// encodeBase32 writes the unpadded base32 encoding of the big-endian bytes of
+// v into dst, matching base32Encoding without allocating.
+func encodeBase32(dst *[base32Len]byte, v uint64) {
+	appendEncoded(dst[:0], v, base32Radix)
+}
+
+// PathSegment returns the canonical form of the ID for use as a URL path
+// segment. It is the base32 encoding, whose alphabet never needs escaping.
+func (id ID) PathSegment() string {
+	return id.Base32()
+}
+
+// ParsePathSegment parses an ID from a URL path segment as produced by
+// PathSegment. Surrounding whitespace and a single trailing slash are ignored.
+func ParsePathSegment(s string) (ID, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(s, "/")
+	return ParseBase32(strings.TrimSpace(s))
+}
+
+// EncodingLengths returns the length in characters of each string encoding
+// ("decimal", "hex", "base32", "base36", "base62" and "base64") for the
+// largest valid ID, which is handy when sizing storage columns. All but
+// decimal are fixed width; decimal is the maximum.
+func EncodingLengths() map[string]int {
+	maxID := ID(math.MaxInt64)
+	return map[string]int{
+		"decimal": len(strconv.FormatInt(maxID.Int64(), 10)),
+		"hex":     len(maxID.Hex()),
+		"base32":  len(maxID.Base32()),
+		"base36":  len(maxID.Base36()),
+		"base62":  len(maxID.Base62()),
+		"base64":  len(maxID.Base64()),
+	}
+}
+
+// MaxBase32Len returns the length of the longest base32 string l's IDs encode
+// to, for sizing VARCHAR columns. Base32 encodings are fixed width, so this is
+// 13 for every layout, full-width ones included; the layout parameter keeps
+// column-sizing code independent of that detail.
+func MaxBase32Len(l Layout) int {
+	if l.FullWidth {
+		return len(UID(math.MaxUint64).Base32())
+	}
+	return len(MaxID(l).Base32())
+}
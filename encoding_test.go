@@ -0,0 +1,193 @@
+package crystal
+
+import (
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestBase62(t *testing.T) {
+	gen := New()
+
+	ids := []ID{0, 1, 61, 62, math.MaxInt64}
+	for i := 0; i < 1000; i++ {
+		ids = append(ids, gen.Generate())
+	}
+
+	encoded := make([]string, len(ids))
+	for i, id := range ids {
+		s := id.Base62()
+		if len(s) != base62Len {
+			t.Fatalf("Base62() length = %d, want %d", len(s), base62Len)
+		}
+
+		parsed, err := ParseBase62(s)
+		if err != nil {
+			t.Fatalf("ParseBase62(%q) failed: %v", s, err)
+		}
+		if parsed != id {
+			t.Fatalf("ParseBase62(%q) = %d, want %d", s, parsed, id)
+		}
+		encoded[i] = s
+	}
+
+	if !sort.StringsAreSorted(encoded[5:]) {
+		t.Error("Base62() strings do not sort in generation order")
+	}
+
+	if parsed, err := ParseBase62("z"); err != nil || parsed != 61 {
+		t.Errorf("ParseBase62(\"z\") = %d, %v, want 61", parsed, err)
+	}
+}
+
+func TestBase36(t *testing.T) {
+	gen := New()
+	ids := []ID{0, 1, 35, 36, 1295, math.MaxInt64}
+	for i := 0; i < 1000; i++ {
+		ids = append(ids, gen.Generate())
+	}
+
+	prev := ""
+	for i, id := range ids {
+		s := id.Base36()
+		if len(s) != base36Len {
+			t.Fatalf("Base36() of %d = %q, want %d characters", id, s, base36Len)
+		}
+		if want := strconv.FormatInt(id.Int64(), 36); strings.TrimLeft(s, "0") != strings.TrimLeft(want, "0") {
+			t.Fatalf("Base36() of %d = %q, want %q padded", id, s, want)
+		}
+		parsed, err := ParseBase36(s)
+		if err != nil || parsed != id {
+			t.Fatalf("ParseBase36(%q) = %d, %v; want %d", s, parsed, err, id)
+		}
+		if i > 6 && s <= prev {
+			t.Fatalf("Base36() strings do not sort in generation order: %q after %q", s, prev)
+		}
+		prev = s
+	}
+
+	for s, want := range map[string]ID{"z": 35, "10": 36, "ZZ": 1295, "1y2p0ij32e8e7": math.MaxInt64} {
+		if parsed, err := ParseBase36(s); err != nil || parsed != want {
+			t.Errorf("ParseBase36(%q) = %d, %v; want %d", s, parsed, err, want)
+		}
+	}
+}
+
+func TestParseBase36Invalid(t *testing.T) {
+	inputs := []string{"", "abc-def", "ab_cd", "abc!", "+1", "-1", "1y2p0ij32e8e8", "00000000000000"}
+	for _, input := range inputs {
+		if _, err := ParseBase36(input); err == nil {
+			t.Errorf("ParseBase36(%q) should fail", input)
+		}
+	}
+}
+
+func TestParseBase62Invalid(t *testing.T) {
+	inputs := []string{"", "abc-def", "zzzzzzzzzzz", "AzL8n0Y58m8", "000000000000"}
+	for _, input := range inputs {
+		if _, err := ParseBase62(input); err == nil {
+			t.Errorf("ParseBase62(%q) should fail", input)
+		}
+	}
+}
+
+func TestEncodingLengths(t *testing.T) {
+	maxID := ID(math.MaxInt64)
+	want := map[string]int{
+		"decimal": len(strconv.FormatInt(maxID.Int64(), 10)),
+		"hex":     len(maxID.Hex()),
+		"base32":  len(maxID.Base32()),
+		"base36":  len(maxID.Base36()),
+		"base62":  len(maxID.Base62()),
+		"base64":  len(maxID.Base64()),
+	}
+
+	got := EncodingLengths()
+	if len(got) != len(want) {
+		t.Fatalf("EncodingLengths() returned %d entries, want %d", len(got), len(want))
+	}
+	for name, n := range want {
+		if got[name] != n {
+			t.Errorf("EncodingLengths()[%q] = %d, want %d", name, got[name], n)
+		}
+	}
+
+	if got["decimal"] != 19 || got["hex"] != 16 || got["base32"] != 13 || got["base36"] != 13 || got["base62"] != 11 || got["base64"] != 11 {
+		t.Errorf("unexpected lengths %v", got)
+	}
+}
+
+func TestPathSegment(t *testing.T) {
+	id := New().Generate()
+	seg := id.PathSegment()
+
+	if esc := url.PathEscape(seg); esc != seg {
+		t.Fatalf("PathSegment() %q needs escaping: %q", seg, esc)
+	}
+
+	for _, in := range []string{
+		seg,
+		seg + "/",
+		"  " + seg + "  ",
+		"\t" + seg + "/\n",
+		" " + seg + " / ",
+	} {
+		got, err := ParsePathSegment(in)
+		if err != nil {
+			t.Fatalf("ParsePathSegment(%q) failed: %v", in, err)
+		}
+		if got != id {
+			t.Errorf("ParsePathSegment(%q) = %d, want %d", in, got, id)
+		}
+	}
+
+	for _, in := range []string{"", "/", seg + "//", "/" + seg, seg + "x"} {
+		if _, err := ParsePathSegment(in); err == nil {
+			t.Errorf("ParsePathSegment(%q) should fail", in)
+		}
+	}
+}
+
+func TestArray(t *testing.T) {
+	id := New().Generate()
+
+	if got := FromBytes(id.Array()); got != id {
+		t.Fatalf("FromBytes(Array()) = %d, want %d", got, id)
+	}
+	if a := ID(0x0102030405060708).Array(); a != [8]byte{1, 2, 3, 4, 5, 6, 7, 8} {
+		t.Fatalf("Array() = %v, want big-endian bytes", a)
+	}
+
+	var sink [8]byte
+	if allocs := testing.AllocsPerRun(1000, func() { sink = id.Array() }); allocs != 0 {
+		t.Fatalf("Array() allocated %v times per call", allocs)
+	}
+	_ = sink
+}
+
+func BenchmarkArray(b *testing.B) {
+	id := New().Generate()
+	var sink [8]byte
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sink = id.Array()
+	}
+	_ = sink
+}
+
+func TestMaxBase32Len(t *testing.T) {
+	wide := DefaultLayout()
+	wide.Timebits = 48
+	full := DefaultLayout()
+	full.FullWidth = true
+
+	for _, l := range []Layout{DefaultLayout(), wide, full} {
+		if got, want := MaxBase32Len(l), len(MaxID(l).Base32()); got != want || got != 13 {
+			t.Errorf("MaxBase32Len(%+v) = %d, want %d", l, got, want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package crystal
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// NewFromEnv creates a Generator configured from environment variables named
+// after prefix, falling back to the package-level configuration for any that
+// are unset or empty:
+//
+//   - {prefix}_EPOCH: the epoch, as RFC 3339 or milliseconds since the Unix
+//     epoch;
+//   - {prefix}_TIMEBITS: the timestamp width, which must be within 40-48;
+//   - {prefix}_NODEBITS: the node width;
+//   - {prefix}_NODE_ID: the node, which must fit the node width.
+//
+// opts are applied after the environment and take precedence over it.
+func NewFromEnv(prefix string, opts ...Option) (*Generator, error) {
+	l := DefaultLayout()
+
+	if v := os.Getenv(prefix + "_EPOCH"); v != "" {
+		epoch, err := parseEnvEpoch(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s_EPOCH: %w", prefix, err)
+		}
+		l.Epoch = epoch
+	}
+
+	if v := os.Getenv(prefix + "_TIMEBITS"); v != "" {
+		bits, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s_TIMEBITS: %w", prefix, err)
+		}
+		if bits < minTimebits || bits > maxTimebits {
+			return nil, fmt.Errorf("%s_TIMEBITS: %w: %d not in %d-%d", prefix, ErrOutOfRange, bits, minTimebits, maxTimebits)
+		}
+		l.Timebits = bits
+	}
+
+	if v := os.Getenv(prefix + "_NODEBITS"); v != "" {
+		bits, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s_NODEBITS: %w", prefix, err)
+		}
+		l.Nodebits = bits
+	}
+
+	envOpts := []Option{WithLayout(l)}
+	if v := os.Getenv(prefix + "_NODE_ID"); v != "" {
+		node, err := strconv.ParseUint(v, 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("%s_NODE_ID: %w", prefix, err)
+		}
+		envOpts = append(envOpts, WithNode(uint16(node)))
+	}
+
+	return NewGenerator(append(envOpts, opts...)...)
+}
+
+// parseEnvEpoch parses an epoch given as RFC 3339 or as milliseconds since the
+// Unix epoch.
+func parseEnvEpoch(v string) (int64, error) {
+	if millis, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return millis, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid epoch %q: want RFC 3339 or milliseconds", v)
+	}
+	return t.UnixMilli(), nil
+}
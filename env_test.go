@@ -0,0 +1,73 @@
+package crystal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv("IDS_EPOCH", "2022-03-01T00:00:00Z")
+	t.Setenv("IDS_TIMEBITS", "44")
+	t.Setenv("IDS_NODEBITS", "6")
+	t.Setenv("IDS_NODE_ID", "37")
+
+	gen, err := NewFromEnv("IDS")
+	if err != nil {
+		t.Fatalf("NewFromEnv() failed: %v", err)
+	}
+
+	l := gen.Layout()
+	if want := time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC).UnixMilli(); l.Epoch != want {
+		t.Errorf("Epoch = %d, want %d", l.Epoch, want)
+	}
+	if l.Timebits != 44 || l.Nodebits != 6 {
+		t.Errorf("Timebits/Nodebits = %d/%d, want 44/6", l.Timebits, l.Nodebits)
+	}
+	if node := l.Node(gen.Generate()); node != 37 {
+		t.Errorf("Node() = %d, want 37", node)
+	}
+
+	t.Setenv("IDS_EPOCH", "1600000000000")
+	gen, err = NewFromEnv("IDS")
+	if err != nil {
+		t.Fatalf("NewFromEnv() with millisecond epoch failed: %v", err)
+	}
+	if l := gen.Layout(); l.Epoch != 1600000000000 {
+		t.Errorf("Epoch = %d, want 1600000000000", l.Epoch)
+	}
+}
+
+func TestNewFromEnvDefaults(t *testing.T) {
+	gen, err := NewFromEnv("CRYSTAL_TEST_UNSET")
+	if err != nil {
+		t.Fatalf("NewFromEnv() failed: %v", err)
+	}
+	if gen.Layout() != DefaultLayout() {
+		t.Errorf("Layout() = %+v, want %+v", gen.Layout(), DefaultLayout())
+	}
+}
+
+func TestNewFromEnvInvalid(t *testing.T) {
+	for _, tc := range []struct {
+		key, value string
+	}{
+		{"BAD_EPOCH", "yesterday"},
+		{"BAD_TIMEBITS", "forty"},
+		{"BAD_TIMEBITS", "50"},
+		{"BAD_NODE_ID", "-1"},
+		{"BAD_NODE_ID", "3"}, // no node bits
+	} {
+		t.Run(tc.key+"="+tc.value, func(t *testing.T) {
+			t.Setenv(tc.key, tc.value)
+			if _, err := NewFromEnv("BAD"); err == nil {
+				t.Fatalf("NewFromEnv() with %s=%q should fail", tc.key, tc.value)
+			}
+		})
+	}
+
+	t.Setenv("BAD_TIMEBITS", "39")
+	if _, err := NewFromEnv("BAD"); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("NewFromEnv() with TIMEBITS=39 error = %v, want ErrOutOfRange", err)
+	}
+}
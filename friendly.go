@@ -0,0 +1,92 @@
+package crystal
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// friendlyAlphabet omits vowels (so codes cannot spell words) and the
+// visually ambiguous characters 0, 1, i, l, o and u.
+const friendlyAlphabet = "23456789bcdfghjkmnpqrstvwxz"
+
+// FriendlyReversibleLen is the shortest friendly code length that still
+// holds every bit of an ID, and therefore the shortest that ParseFriendlyCode
+// accepts.
+const FriendlyReversibleLen = 14
+
+// ErrFriendlyCodeLossy is returned by ParseFriendlyCode for codes shorter than
+// FriendlyReversibleLen, which cannot be mapped back to an ID.
+var ErrFriendlyCodeLossy = errors.New("crystal: friendly code too short to reverse")
+
+// FriendlyCode returns a human-friendly code of exactly length characters
+// derived from the ID. The ID is scrambled first, so consecutive IDs yield
+// unrelated codes.
+//
+// Codes of FriendlyReversibleLen characters or more are reversible with
+// ParseFriendlyCode. Shorter codes (e.g. 6–8 characters for referral codes)
+// keep only part of the scrambled value: they are lossy, cannot be parsed
+// back, and distinct IDs may share a code, so callers must check them for
+// collisions before handing them out.
+func (id ID) FriendlyCode(length int) string {
+	if length <= 0 {
+		return ""
+	}
+
+	base := uint64(len(friendlyAlphabet))
+	v := friendlyMix(id.Uint64())
+
+	b := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		b[i] = friendlyAlphabet[v%base]
+		v /= base
+	}
+	return string(b)
+}
+
+// ParseFriendlyCode maps a friendly code of at least FriendlyReversibleLen
+// characters back to its ID. Parsing is case-insensitive.
+func ParseFriendlyCode(s string) (ID, error) {
+	if len(s) < FriendlyReversibleLen {
+		return 0, ErrFriendlyCodeLossy
+	}
+
+	base := uint64(len(friendlyAlphabet))
+	var v uint64
+	for i, c := range strings.ToLower(s) {
+		digit := strings.IndexRune(friendlyAlphabet, c)
+		if digit < 0 {
+			return 0, fmt.Errorf("invalid friendly code character %q at %d", c, i)
+		}
+		if v > (math.MaxUint64-uint64(digit))/base {
+			return 0, fmt.Errorf("friendly code out of range: %s", s)
+		}
+		v = v*base + uint64(digit)
+	}
+
+	u := friendlyUnmix(v)
+	if u > math.MaxInt64 {
+		return 0, fmt.Errorf("friendly code out of range: %s", s)
+	}
+	return ID(u), nil
+}
+
+// friendlyMix is a bijective scramble of x (the first half of the murmur3
+// finalizer), used so that neighbouring IDs produce unrelated codes.
+func friendlyMix(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	return x
+}
+
+// friendlyUnmix inverts friendlyMix. A right xor-shift by 33 is its own
+// inverse for 64-bit values, and 0x4f74430c22a54005 is the multiplicative
+// inverse of 0xff51afd7ed558ccd modulo 2^64.
+func friendlyUnmix(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0x4f74430c22a54005
+	x ^= x >> 33
+	return x
+}
@@ -0,0 +1,70 @@
+package crystal
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestFriendlyCodeReversible(t *testing.T) {
+	gen := New()
+
+	ids := []ID{0, 1, math.MaxInt64}
+	for i := 0; i < 1000; i++ {
+		ids = append(ids, gen.Generate())
+	}
+
+	for _, id := range ids {
+		code := id.FriendlyCode(FriendlyReversibleLen)
+		if len(code) != FriendlyReversibleLen {
+			t.Fatalf("FriendlyCode() length = %d, want %d", len(code), FriendlyReversibleLen)
+		}
+		if strings.Trim(code, friendlyAlphabet) != "" {
+			t.Fatalf("FriendlyCode() %q contains characters outside the alphabet", code)
+		}
+
+		parsed, err := ParseFriendlyCode(strings.ToUpper(code))
+		if err != nil {
+			t.Fatalf("ParseFriendlyCode(%q) failed: %v", code, err)
+		}
+		if parsed != id {
+			t.Fatalf("ParseFriendlyCode(%q) = %d, want %d", code, parsed, id)
+		}
+	}
+}
+
+func TestFriendlyCodeLossy(t *testing.T) {
+	gen := New()
+
+	a := gen.Generate()
+	b := gen.Generate()
+
+	codeA := a.FriendlyCode(8)
+	if len(codeA) != 8 {
+		t.Fatalf("FriendlyCode(8) length = %d", len(codeA))
+	}
+	if codeA != a.FriendlyCode(8) {
+		t.Fatal("FriendlyCode() is not deterministic")
+	}
+	if codeA == b.FriendlyCode(8) {
+		t.Errorf("consecutive IDs produced the same code %q", codeA)
+	}
+
+	if _, err := ParseFriendlyCode(codeA); !errors.Is(err, ErrFriendlyCodeLossy) {
+		t.Fatalf("ParseFriendlyCode() error = %v, want ErrFriendlyCodeLossy", err)
+	}
+
+	if got := a.FriendlyCode(0); got != "" {
+		t.Errorf("FriendlyCode(0) = %q, want empty", got)
+	}
+}
+
+func TestParseFriendlyCodeInvalid(t *testing.T) {
+	if _, err := ParseFriendlyCode("aaaaaaaaaaaaaa"); err == nil {
+		t.Error("ParseFriendlyCode() should fail for characters outside the alphabet")
+	}
+	if _, err := ParseFriendlyCode(strings.Repeat("z", 20)); err == nil {
+		t.Error("ParseFriendlyCode() should fail for out-of-range codes")
+	}
+}
@@ -1,3 +1,3 @@
 module github.com/kwo/crystal
 
-go 1.21
+go 1.23
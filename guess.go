@@ -0,0 +1,50 @@
+package crystal
+
+import "time"
+
+// knownEpochs are the epochs GuessEpoch tries, in milliseconds since the Unix
+// epoch.
+//
+//nolint:gochecknoglobals
+var knownEpochs = []int64{
+	defaultEpochMillis, // crystal: 2020-01-01
+	0,                  // Unix: 1970-01-01
+	1288834974657,      // Twitter snowflake: 2010-11-04
+	1420070400000,      // Discord: 2015-01-01
+}
+
+// guessEpochFloor is the earliest time GuessEpoch considers plausible.
+const guessEpochFloor = int64(1420070400000) // 2015-01-01 00:00:00 UTC
+
+// GuessEpoch tries to identify the epoch of an ID from an unknown source whose
+// timestamp occupies the top timebits bits below the sign bit. It decodes i
+// under a set of well-known epochs (crystal's 2020 default, Unix, Twitter
+// and Discord) and returns the epoch whose decoded time is plausible, that is
+// between 2015 and now. When several epochs qualify, the one yielding the
+// most recent time wins, which is the most recent of those epochs: an older
+// epoch pulls the decoded time earlier. The bool is false when no epoch gives
+// a plausible time.
+//
+// The result is a heuristic for forensic decoding, not a proof.
+func GuessEpoch(i int64, timebits int) (int64, bool) {
+	if i < 0 {
+		return 0, false
+	}
+
+	l := Layout{Timebits: timebits}
+	millis := int64(uint64(i) >> l.timeShift()) //nolint:gosec
+	now := time.Now().UnixMilli()
+
+	best, found := int64(0), false
+	var bestTime int64
+	for _, epoch := range knownEpochs {
+		t := millis + epoch
+		if t < guessEpochFloor || t > now {
+			continue
+		}
+		if !found || t > bestTime {
+			best, bestTime, found = epoch, t, true
+		}
+	}
+	return best, found
+}
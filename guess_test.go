@@ -0,0 +1,42 @@
+package crystal
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestGuessEpoch(t *testing.T) {
+	ts := time.Now().Add(-48 * time.Hour)
+
+	for _, tc := range []struct {
+		name     string
+		epoch    int64
+		timebits int
+	}{
+		{"crystal", defaultEpochMillis, 42},
+		{"unix", 0, 42},
+		{"twitter", 1288834974657, 41},
+		{"discord", 1420070400000, 42},
+	} {
+		l := Layout{Epoch: tc.epoch, Timebits: tc.timebits}
+		id := l.compose(ts.UnixMilli()-tc.epoch, 0, 12345)
+
+		got, ok := GuessEpoch(id.Int64(), tc.timebits)
+		if !ok {
+			t.Errorf("%s: GuessEpoch() found no epoch", tc.name)
+			continue
+		}
+		if got != tc.epoch {
+			t.Errorf("%s: GuessEpoch() = %d, want %d", tc.name, got, tc.epoch)
+		}
+	}
+
+	// A timestamp in the future under every known epoch is not plausible.
+	if _, ok := GuessEpoch(math.MaxInt64, 42); ok {
+		t.Error("GuessEpoch(MaxInt64) should not find a plausible epoch")
+	}
+	if _, ok := GuessEpoch(-1, 42); ok {
+		t.Error("GuessEpoch(-1) should not find a plausible epoch")
+	}
+}
@@ -0,0 +1,51 @@
+package crystal
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// history is a ring of the most recently generated IDs (see WithHistory).
+// Writers run under the generator lock; History reads it without locking.
+type history struct {
+	ids  []atomic.Uint64
+	next atomic.Uint64
+}
+
+// WithHistory makes the generator remember the last n IDs it issued, for
+// inspection through History when a collision is reported. Without it no
+// history is kept and generation pays only a nil check.
+func WithHistory(n int) Option {
+	return func(g *Generator) error {
+		if n < 1 {
+			return fmt.Errorf("history size must be at least 1: %d", n)
+		}
+		g.history = &history{ids: make([]atomic.Uint64, n)}
+		return nil
+	}
+}
+
+// History returns up to the last n IDs the generator issued (see
+// WithHistory), oldest first, or nil if the generator keeps no history. It
+// does not block generation; IDs issued while it runs may or may not appear.
+func (g *Generator) History() []ID {
+	h := g.history
+	if h == nil {
+		return nil
+	}
+
+	end := h.next.Load()
+	n := min(end, uint64(len(h.ids)))
+	ids := make([]ID, 0, n)
+	for i := end - n; i < end; i++ {
+		ids = append(ids, ID(h.ids[i%uint64(len(h.ids))].Load())) //nolint:gosec
+	}
+	return ids
+}
+
+// record appends id to the ring, overwriting the oldest entry once full.
+func (h *history) record(id ID) {
+	i := h.next.Load()
+	h.ids[i%uint64(len(h.ids))].Store(id.Uint64())
+	h.next.Store(i + 1)
+}
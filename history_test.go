@@ -0,0 +1,36 @@
+package crystal
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWithHistory(t *testing.T) {
+	const n = 16
+	gen := New(WithHistory(n))
+
+	if got := gen.History(); len(got) != 0 {
+		t.Fatalf("History() before generating = %v, want empty", got)
+	}
+
+	ids := make([]ID, 3*n+5)
+	for i := range ids {
+		ids[i] = gen.Generate()
+	}
+
+	got := gen.History()
+	if want := ids[len(ids)-n:]; !slices.Equal(got, want) {
+		t.Fatalf("History() = %v, want %v", got, want)
+	}
+}
+
+func TestWithHistoryDisabled(t *testing.T) {
+	gen := New()
+	gen.Generate()
+	if got := gen.History(); got != nil {
+		t.Fatalf("History() = %v, want nil", got)
+	}
+	if _, err := NewGenerator(WithHistory(0)); err == nil {
+		t.Fatal("NewGenerator(WithHistory(0)) succeeded, want error")
+	}
+}
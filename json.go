@@ -0,0 +1,98 @@
+package crystal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// MarshalJSON encodes the ID as a JSON string holding its base32 form.
+func (id ID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(id.Base32())
+}
+
+// UnmarshalJSON decodes an ID from any of the forms clients commonly send:
+//
+//   - a JSON number (12345) is read as the decimal int64 value;
+//   - a 13 character JSON string ("0d6av3w2kc002") is read as base32, the
+//     form MarshalJSON produces, even when it consists solely of digits;
+//   - any other JSON string made up only of digits ("12345") is read as
+//     decimal, and anything else is rejected.
+//
+// MarshalJSON output therefore always round-trips, while a decimal string of
+// exactly 13 digits must be sent as a JSON number instead. JSON null leaves
+// the ID unchanged.
+func (id *ID) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) == 0 || data[0] != '"' {
+		i, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid ID number %s: %w", data, err)
+		}
+		if i < 0 {
+			return fmt.Errorf("invalid ID number %s: negative", data)
+		}
+		*id = ID(i)
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	if len(s) != base32Len && isDecimal(s) {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid ID string %q: %w", s, err)
+		}
+		*id = ID(i)
+		return nil
+	}
+
+	parsed, err := ParseBase32(s)
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// JSONNumber returns the ID's decimal form as a json.Number, for use with
+// decoders configured with UseNumber.
+func (id ID) JSONNumber() json.Number {
+	return json.Number(strconv.FormatInt(id.Int64(), 10))
+}
+
+// FromJSONNumber converts a json.Number holding a decimal integer to an ID.
+// It returns an error wrapping ErrOutOfRange for negative numbers and numbers
+// beyond math.MaxInt64.
+func FromJSONNumber(n json.Number) (ID, error) {
+	i, err := strconv.ParseInt(string(n), 10, 64)
+	if errors.Is(err, strconv.ErrRange) || (err == nil && i < 0) {
+		return 0, fmt.Errorf("%w: %s", ErrOutOfRange, n)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid ID number %s: %w", n, err)
+	}
+	return ID(i), nil
+}
+
+// isDecimal reports whether s is a non-empty run of ASCII digits.
+func isDecimal(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,145 @@
+package crystal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	gen := New()
+	id := gen.Generate()
+
+	data, err := json.Marshal(id)
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	if string(data) != `"`+id.Base32()+`"` {
+		t.Fatalf("Marshal() = %s, want quoted base32", data)
+	}
+
+	var parsed ID
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+	if parsed != id {
+		t.Fatalf("round trip returned %d, want %d", parsed, id)
+	}
+}
+
+func TestUnmarshalJSONForms(t *testing.T) {
+	gen := New()
+	id := gen.Generate()
+	decimal := strconv.FormatInt(id.Int64(), 10)
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "quoted decimal", input: `"` + decimal + `"`},
+		{name: "number", input: decimal},
+		{name: "base32 string", input: `"` + id.Base32() + `"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var parsed ID
+			if err := json.Unmarshal([]byte(tt.input), &parsed); err != nil {
+				t.Fatalf("Unmarshal(%s) failed: %v", tt.input, err)
+			}
+			if parsed != id {
+				t.Fatalf("Unmarshal(%s) = %d, want %d", tt.input, parsed, id)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSONNull(t *testing.T) {
+	id := ID(42)
+	if err := json.Unmarshal([]byte("null"), &id); err != nil {
+		t.Fatalf("Unmarshal(null) failed: %v", err)
+	}
+	if id != 42 {
+		t.Fatalf("Unmarshal(null) changed the ID to %d", id)
+	}
+
+	var payload struct {
+		ID *ID `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(`{"id":null}`), &payload); err != nil {
+		t.Fatalf("Unmarshal() failed: %v", err)
+	}
+	if payload.ID != nil {
+		t.Fatalf("expected nil ID pointer, got %d", *payload.ID)
+	}
+}
+
+func TestUnmarshalJSONInvalid(t *testing.T) {
+	inputs := []string{`-5`, `1.5`, `"99999999999999999999"`, `"not-base32!"`, `true`}
+	for _, input := range inputs {
+		var id ID
+		if err := json.Unmarshal([]byte(input), &id); err == nil {
+			t.Errorf("Unmarshal(%s) should fail", input)
+		}
+	}
+}
+
+func TestJSONNumber(t *testing.T) {
+	id := New().Generate()
+
+	data, err := json.Marshal(map[string]json.Number{"id": id.JSONNumber()})
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var payload map[string]any
+	if err := dec.Decode(&payload); err != nil {
+		t.Fatalf("Decode() failed: %v", err)
+	}
+	n, ok := payload["id"].(json.Number)
+	if !ok {
+		t.Fatalf("decoded %T, want json.Number", payload["id"])
+	}
+
+	got, err := FromJSONNumber(n)
+	if err != nil {
+		t.Fatalf("FromJSONNumber() failed: %v", err)
+	}
+	if got != id {
+		t.Fatalf("FromJSONNumber() = %d, want %d", got, id)
+	}
+
+	for _, in := range []json.Number{"9223372036854775808", "-1"} {
+		if _, err := FromJSONNumber(in); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("FromJSONNumber(%s) error = %v, want ErrOutOfRange", in, err)
+		}
+	}
+	if _, err := FromJSONNumber("1.5"); err == nil || errors.Is(err, ErrOutOfRange) {
+		t.Errorf("FromJSONNumber(1.5) error = %v, want a syntax error", err)
+	}
+}
+
+func TestJSONRoundTripSmallIDs(t *testing.T) {
+	ids := []ID{0, 1, 2, 9, 10, 31, 32, 12345, 1 << 40}
+	gen := New()
+	for i := 0; i < 100; i++ {
+		ids = append(ids, gen.Generate())
+	}
+	for _, id := range ids {
+		data, err := json.Marshal(id)
+		if err != nil {
+			t.Fatalf("Marshal(%d) failed: %v", id, err)
+		}
+		var parsed ID
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			t.Fatalf("Unmarshal(%s) failed: %v", data, err)
+		}
+		if parsed != id {
+			t.Fatalf("Unmarshal(Marshal(%d)) = %d via %s", id, parsed, data)
+		}
+	}
+}
@@ -0,0 +1,339 @@
+package crystal
+
+import (
+	"fmt"
+	"math/bits"
+	"time"
+)
+
+// Layout describes how the 63 bits of an ID are split between the timestamp,
+// node and sequence components, and which epoch the timestamp counts from.
+//
+// From the most significant bit down an ID holds:
+//
+//	| 1 unused | Versionbits version | Timebits timestamp | Kindbits kind | Nodebits node | remaining bits sequence |
+//
+// A FullWidth layout has no unused bit; its values are UIDs (see
+// WithFullWidth).
+type Layout struct {
+	// Epoch is the timestamp base in milliseconds since the Unix epoch.
+	Epoch int64
+	// Versionbits is the width of the layout-version tag in the top bits
+	// (default 0, at most 2; see RegisterLayout).
+	Versionbits int
+	// Version is the tag stamped into IDs composed under this layout.
+	Version uint8
+	// Timebits is the width of the timestamp (clamped to 40-48, or 32-48 at
+	// Second precision).
+	Timebits int
+	// Kindbits is the width of the entity-kind tag (default 0, at most 8).
+	Kindbits int
+	// Nodebits is the width of the node identifier (default 0). It is
+	// clamped so that at least one sequence bit remains.
+	Nodebits int
+	// Workerbits is the width of the worker field in the low bits of the
+	// node; the node's remaining high bits hold the datacenter.
+	Workerbits int
+	// FullWidth gives the unused sign bit to the sequence, for a total of 64
+	// bits. Such values only fit a uint64.
+	FullWidth bool
+	// ScatteredTime stores the timestamp bit-reversed (see
+	// WithScatteredTime).
+	ScatteredTime bool
+	// Precision is the unit of the timestamp (default Millisecond; see
+	// WithPrecision).
+	Precision Precision
+}
+
+// DefaultLayout returns the layout described by the package-level Epoch,
+// Versionbits, LayoutVersion, Timebits, Kindbits, Nodebits and Workerbits
+// variables. ID methods that take no Layout decode with it (or with the
+// registered layout named by the ID's version tag), and New snapshots it for
+// every generator it creates.
+func DefaultLayout() Layout {
+	return Layout{
+		Epoch:       Epoch,
+		Versionbits: Versionbits,
+		Version:     LayoutVersion,
+		Timebits:    Timebits,
+		Kindbits:    Kindbits,
+		Nodebits:    Nodebits,
+		Workerbits:  Workerbits,
+	}
+}
+
+// Time returns the timestamp embedded in id under this layout.
+func (l Layout) Time(id ID) time.Time {
+	return l.timeAt(l.millis(id))
+}
+
+// Kind returns the entity-kind tag embedded in id under this layout, or 0 when
+// the layout has no kind bits.
+func (l Layout) Kind(id ID) uint8 {
+	return uint8((id.Uint64() >> l.kindShift()) & l.kindMask()) //nolint:gosec
+}
+
+// Node returns the node identifier embedded in id under this layout, or 0 when
+// the layout has no node bits.
+func (l Layout) Node(id ID) uint64 {
+	return (id.Uint64() >> l.nodeShift()) & l.nodeMask()
+}
+
+// Datacenter returns the datacenter field (the node's high bits above
+// Workerbits) embedded in id under this layout.
+func (l Layout) Datacenter(id ID) uint8 {
+	return uint8(l.Node(id) >> uint(l.workerbits())) //nolint:gosec
+}
+
+// Worker returns the worker field (the node's low Workerbits bits) embedded
+// in id under this layout.
+func (l Layout) Worker(id ID) uint8 {
+	return uint8(l.Node(id) & (uint64(1)<<uint(l.workerbits()) - 1)) //nolint:gosec
+}
+
+// Step returns the sequence component of id under this layout.
+func (l Layout) Step(id ID) uint64 {
+	return id.Uint64() & l.stepMask()
+}
+
+// KeyParts splits id into a big-endian timestamp prefix (version and time
+// bits) and a big-endian sequence suffix (kind, node and step bits), each
+// using the fewest whole bytes that hold its fields. Concatenating the parts sorts like the ID itself, so
+// they suit composite keys where only the time prefix is indexed.
+func (l Layout) KeyParts(id ID) (timePrefix []byte, seqSuffix []byte) {
+	shift := l.timeShift()
+	u := id.Uint64()
+
+	timePrefix = putUintBytes((l.versionbits()+l.timebits()+7)/8, u>>shift)
+	seqSuffix = putUintBytes((int(shift)+7)/8, u&(uint64(1)<<shift-1))
+	return timePrefix, seqSuffix
+}
+
+// Rebase re-encodes id, issued under layout from, so that it carries the same
+// absolute timestamp, node and step under layout to. The timestamp is
+// converted between the layouts' precisions, truncating to to's unit. It
+// returns an error wrapping ErrOutOfRange if the timestamp lies before to's
+// epoch or beyond its time bits, or if the node or step does not fit to's
+// fields.
+func Rebase(id ID, from, to Layout) (ID, error) {
+	abs := from.Time(id)
+	kind := uint64(from.Kind(id))
+	node := from.Node(id)
+	step := from.Step(id)
+
+	millis := to.ticksSince(abs)
+	if millis < 0 || millis > int64(1)<<uint(to.timebits())-1 {
+		return 0, fmt.Errorf("%w: timestamp %s does not fit the target layout", ErrOutOfRange, abs.UTC())
+	}
+	if kind > to.kindMask() {
+		return 0, fmt.Errorf("%w: kind %d does not fit in %d kind bits", ErrOutOfRange, kind, to.kindbits())
+	}
+	if node > to.nodeMask() {
+		return 0, fmt.Errorf("%w: node %d does not fit in %d node bits", ErrOutOfRange, node, to.nodebits())
+	}
+	if step > to.stepMask() {
+		return 0, fmt.Errorf("%w: step %d does not fit in %d step bits", ErrOutOfRange, step, to.stepBits())
+	}
+	return to.withKind(to.compose(millis, node, step), kind), nil
+}
+
+// putUintBytes returns the n least significant bytes of v in big-endian order.
+func putUintBytes(n int, v uint64) []byte {
+	b := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+	return b
+}
+
+// compose packs a timestamp (milliseconds since l.Epoch), node and step into an
+// ID. Components wider than their fields are truncated.
+func (l Layout) compose(millis int64, node, step uint64) ID {
+	t := uint64(millis) //nolint:gosec
+	if l.ScatteredTime {
+		t = l.scatter(t)
+	}
+	return ID((uint64(l.Version)&l.versionMask())<<l.versionShift() | //nolint:gosec
+		(t&l.timeMask())<<l.timeShift() |
+		((node & l.nodeMask()) << l.nodeShift()) |
+		(step & l.stepMask()))
+}
+
+// withKind stamps kind into the kind field of id. Kinds wider than the field
+// are truncated.
+func (l Layout) withKind(id ID, kind uint64) ID {
+	return id | ID((kind&l.kindMask())<<l.kindShift()) //nolint:gosec
+}
+
+// millis returns the timestamp field of id (milliseconds since l.Epoch),
+// undoing ScatteredTime.
+func (l Layout) millis(id ID) int64 {
+	t := (id.Uint64() >> l.timeShift()) & l.timeMask()
+	if l.ScatteredTime {
+		t = l.scatter(t)
+	}
+	return int64(t) //nolint:gosec
+}
+
+// scatter reverses the order of the low timebits bits of t, discarding any
+// higher bits. It is its own inverse.
+func (l Layout) scatter(t uint64) uint64 {
+	return bits.Reverse64(t) >> uint(64-l.timebits())
+}
+
+// totalBits returns the width of the whole value: 63 bits, or 64 for a
+// FullWidth layout.
+func (l Layout) totalBits() int {
+	if l.FullWidth {
+		return totalBits + 1
+	}
+	return totalBits
+}
+
+// fieldBits returns the bits left for time, kind, node and sequence below the
+// version tag.
+func (l Layout) fieldBits() int {
+	return l.totalBits() - l.versionbits()
+}
+
+// versionbits clamps Versionbits to at most 2 bits.
+func (l Layout) versionbits() int {
+	v := l.Versionbits
+	if v < 0 {
+		v = 0
+	}
+	if v > maxVersionbits {
+		v = maxVersionbits
+	}
+	return v
+}
+
+// timebits clamps Timebits into the supported range (40-48 bits, or 32-48 at
+// Second precision) so it always leaves room for at least one sequence bit.
+func (l Layout) timebits() int {
+	t := l.Timebits
+	if min := l.minTimebits(); t < min {
+		t = min
+	}
+	if t > maxTimebits {
+		t = maxTimebits
+	}
+	return t
+}
+
+// minTimebits returns the narrowest timestamp the layout's precision allows.
+func (l Layout) minTimebits() int {
+	if l.Precision == Second {
+		return secondTimebits
+	}
+	return minTimebits
+}
+
+// kindbits clamps Kindbits to at most 8 bits, never consuming the last
+// sequence bit.
+func (l Layout) kindbits() int {
+	k := l.Kindbits
+	if k < 0 {
+		k = 0
+	}
+	if k > maxKindbits {
+		k = maxKindbits
+	}
+	if limit := l.fieldBits() - l.timebits() - 1; k > limit {
+		k = limit
+	}
+	return k
+}
+
+// nodebits clamps Nodebits so the node never consumes the last sequence bit.
+func (l Layout) nodebits() int {
+	n := l.Nodebits
+	if n < 0 {
+		n = 0
+	}
+	if limit := l.fieldBits() - l.timebits() - l.kindbits() - 1; n > limit {
+		n = limit
+	}
+	return n
+}
+
+// workerbits clamps Workerbits into the node field.
+func (l Layout) workerbits() int {
+	w := l.Workerbits
+	if w < 0 {
+		w = 0
+	}
+	if n := l.nodebits(); w > n {
+		w = n
+	}
+	return w
+}
+
+// stepBits returns how many bits are available for the sequence component
+// (total bits minus time, kind and node bits, with a minimum of one).
+func (l Layout) stepBits() int {
+	bits := l.fieldBits() - l.timebits() - l.kindbits() - l.nodebits()
+	if bits < 1 {
+		return 1
+	}
+	return bits
+}
+
+// timeShift returns the shift applied when packing or unpacking the timestamp.
+func (l Layout) timeShift() uint {
+	return uint(l.kindbits() + l.nodebits() + l.stepBits())
+}
+
+// versionShift returns the shift applied when packing or unpacking the
+// version tag.
+func (l Layout) versionShift() uint {
+	return uint(l.fieldBits())
+}
+
+// kindShift returns the shift applied when packing or unpacking the kind.
+func (l Layout) kindShift() uint {
+	return uint(l.nodebits() + l.stepBits())
+}
+
+// nodeShift returns the shift applied when packing or unpacking the node.
+func (l Layout) nodeShift() uint {
+	return uint(l.stepBits())
+}
+
+// stepMask returns a mask that isolates the sequence bits in the ID.
+func (l Layout) stepMask() uint64 {
+	return (uint64(1) << uint(l.stepBits())) - 1
+}
+
+// versionMask returns a mask for version tags (applied before shifting).
+func (l Layout) versionMask() uint64 {
+	return (uint64(1) << uint(l.versionbits())) - 1
+}
+
+// timeMask returns a mask for timestamps (applied before shifting).
+func (l Layout) timeMask() uint64 {
+	return (uint64(1) << uint(l.timebits())) - 1
+}
+
+// kindMask returns a mask for kind values (applied before shifting).
+func (l Layout) kindMask() uint64 {
+	return (uint64(1) << uint(l.kindbits())) - 1
+}
+
+// nodeMask returns a mask for node values (applied before shifting).
+func (l Layout) nodeMask() uint64 {
+	return (uint64(1) << uint(l.nodebits())) - 1
+}
+
+// stepSeedMask returns a mask that caps the initial counter seed to the lower
+// half of the step's range so we never start near the rollover boundary.
+// With a single step bit the seed is always 0, so every millisecond holds
+// exactly two IDs (steps 0 and 1) before the generator moves on to the next.
+func (l Layout) stepSeedMask() uint64 {
+	bits := l.stepBits()
+	if bits <= 1 {
+		return 0
+	}
+	return (uint64(1) << uint(bits-1)) - 1
+}
@@ -0,0 +1,189 @@
+package crystal
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLayoutCompose(t *testing.T) {
+	l := Layout{
+		Epoch:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli(),
+		Timebits: 42,
+		Nodebits: 5,
+	}
+
+	if l.stepBits() != 16 {
+		t.Fatalf("expected 16 step bits, got %d", l.stepBits())
+	}
+
+	created := time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC)
+	id := l.compose(created.UnixMilli()-l.Epoch, 17, 1234)
+
+	if !l.Time(id).Equal(created) {
+		t.Errorf("Time() = %v, want %v", l.Time(id), created)
+	}
+	if l.Node(id) != 17 {
+		t.Errorf("Node() = %d, want 17", l.Node(id))
+	}
+	if l.Step(id) != 1234 {
+		t.Errorf("Step() = %d, want 1234", l.Step(id))
+	}
+}
+
+func TestLayoutNodebitsClamp(t *testing.T) {
+	l := Layout{Timebits: maxTimebits, Nodebits: 100}
+	if l.stepBits() != 1 {
+		t.Fatalf("expected node bits to leave one step bit, got %d", l.stepBits())
+	}
+	if int(l.timeShift()) != totalBits-maxTimebits {
+		t.Fatalf("unexpected time shift %d", l.timeShift())
+	}
+
+	l.Nodebits = -3
+	if l.nodebits() != 0 {
+		t.Fatalf("expected negative node bits to clamp to 0, got %d", l.nodebits())
+	}
+}
+
+func TestKeyParts(t *testing.T) {
+	gen := New()
+
+	for i := 0; i < 100; i++ {
+		id := gen.Generate()
+		prefix, suffix := id.KeyParts()
+
+		if len(prefix) != 6 || len(suffix) != 3 {
+			t.Fatalf("unexpected part lengths %d and %d", len(prefix), len(suffix))
+		}
+
+		var timestamp, seq uint64
+		for _, b := range prefix {
+			timestamp = timestamp<<8 | uint64(b)
+		}
+		for _, b := range suffix {
+			seq = seq<<8 | uint64(b)
+		}
+
+		rebuilt := ID(timestamp<<currentTimeShift() | seq) //nolint:gosec
+		if rebuilt != id {
+			t.Fatalf("rebuilt ID %d, want %d", rebuilt, id)
+		}
+	}
+
+	l := Layout{Timebits: 40}
+	prefix, suffix := l.KeyParts(ID(1)<<23 | 5)
+	if len(prefix) != 5 || prefix[4] != 1 {
+		t.Fatalf("unexpected 40-bit time prefix %v", prefix)
+	}
+	if len(suffix) != 3 || suffix[2] != 5 {
+		t.Fatalf("unexpected 23-bit sequence suffix %v", suffix)
+	}
+
+	// The version tag sits above the timestamp and stays in the prefix.
+	versioned := Layout{Versionbits: 2, Version: 3, Timebits: 40}
+	prefix, _ = versioned.KeyParts(versioned.compose(1, 0, 0))
+	if want := []byte{3, 0, 0, 0, 0, 1}; !bytes.Equal(prefix, want) {
+		t.Fatalf("versioned time prefix = %v, want %v", prefix, want)
+	}
+}
+
+func TestRebase(t *testing.T) {
+	from := Layout{
+		Epoch:    time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli(),
+		Timebits: 42,
+		Nodebits: 4,
+	}
+	to := Layout{
+		Epoch:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli(),
+		Timebits: 44,
+		Nodebits: 6,
+	}
+
+	ts := time.Date(2024, 6, 1, 12, 30, 0, 123_000_000, time.UTC)
+	id := from.compose(ts.UnixMilli()-from.Epoch, 9, 4321)
+
+	got, err := Rebase(id, from, to)
+	if err != nil {
+		t.Fatalf("Rebase() failed: %v", err)
+	}
+	if !to.Time(got).Equal(from.Time(id)) {
+		t.Errorf("rebased time = %v, want %v", to.Time(got), from.Time(id))
+	}
+	if to.Node(got) != 9 || to.Step(got) != 4321 {
+		t.Errorf("rebased node/step = %d/%d, want 9/4321", to.Node(got), to.Step(got))
+	}
+
+	// Before the target epoch.
+	early := from.compose(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()-from.Epoch, 0, 1)
+	if _, err := Rebase(early, from, to); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Rebase(before epoch) error = %v, want ErrOutOfRange", err)
+	}
+
+	// A step too wide for the target's sequence bits.
+	narrow := to
+	narrow.Nodebits = 20
+	if _, err := Rebase(from.compose(ts.UnixMilli()-from.Epoch, 0, from.stepMask()), from, narrow); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Rebase(wide step) error = %v, want ErrOutOfRange", err)
+	}
+}
+
+func TestRebasePrecision(t *testing.T) {
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	micro := Layout{Epoch: epoch, Timebits: maxTimebits, Precision: Microsecond}
+	milli := Layout{Epoch: epoch, Timebits: 41}
+
+	ts := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	id := micro.compose(micro.ticksSince(ts), 0, 77)
+
+	got, err := Rebase(id, micro, milli)
+	if err != nil {
+		t.Fatalf("Rebase(micro, milli) failed: %v", err)
+	}
+	if !milli.Time(got).Equal(ts) || milli.Step(got) != 77 {
+		t.Errorf("rebased time/step = %v/%d, want %v/77", milli.Time(got).UTC(), milli.Step(got), ts)
+	}
+
+	back, err := Rebase(got, milli, micro)
+	if err != nil || back != id {
+		t.Errorf("Rebase(milli, micro) = %d, %v; want %d", back, err, id)
+	}
+}
+
+func TestScatteredTime(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newManualClock(start)
+	gen := New(WithClock(clock), WithScatteredTime())
+	l := gen.Layout()
+
+	tops := make(map[uint64]bool)
+	for i := 0; i < 128; i++ {
+		id := gen.Generate()
+		want := start.Add(time.Duration(i) * time.Millisecond)
+		if got := l.Time(id); !got.Equal(want) {
+			t.Fatalf("Time() = %v, want %v", got, want)
+		}
+		if id <= 0 {
+			t.Fatalf("scattered ID %d not positive", id)
+		}
+
+		// Round-trip through the layout's components.
+		if again := l.compose(l.millis(id), l.Node(id), l.Step(id)); again != id {
+			t.Fatalf("compose(decompose(%d)) = %d", id, again)
+		}
+
+		tops[id.Uint64()>>56] = true
+		clock.Add(time.Millisecond)
+	}
+
+	// Consecutive milliseconds differ in their low bits, which scattering
+	// moves to the top of the ID.
+	if len(tops) < 64 {
+		t.Errorf("128 consecutive milliseconds used only %d distinct top bytes", len(tops))
+	}
+
+	if plain := DefaultLayout(); plain.scatter(plain.scatter(12345)) != 12345 {
+		t.Error("scatter is not its own inverse")
+	}
+}
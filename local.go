@@ -0,0 +1,99 @@
+package crystal
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// maxLocalNodebits caps GoroutineLocal pools at 65,536 generators, the range
+// of WithNode.
+const maxLocalNodebits = 16
+
+// GoroutineLocal returns a function that generates IDs from a pool of
+// 2^nodeBits generators, each stamping its own node into the package-level
+// layout with Nodebits set to nodeBits. Concurrent callers are spread across
+// the pool and each call grabs a generator no other goroutine is using, so
+// callers never queue on a shared lock; only when every generator is busy does
+// a call wait for one. At most 2^nodeBits goroutines therefore generate in
+// parallel, and the node bits are taken from the sequence space.
+//
+// IDs from the returned function are unique and ordered per node, but not
+// ordered across nodes. Decode them with the layout described above (e.g.
+// set Nodebits or use Layout.Node). GoroutineLocal panics if nodeBits is zero,
+// larger than 16, or leaves no sequence bits under the current Timebits.
+func GoroutineLocal(nodeBits uint) func() ID {
+	if nodeBits == 0 || nodeBits > maxLocalNodebits {
+		panic(fmt.Sprintf("crystal: nodeBits out of range: %d", nodeBits))
+	}
+
+	layout := DefaultLayout()
+	layout.Nodebits = int(nodeBits)
+	layout.Workerbits = 0
+	if layout.nodebits() != int(nodeBits) {
+		panic(fmt.Sprintf("crystal: %d node bits leave no sequence bits", nodeBits))
+	}
+
+	seed := calculateNodeSeed()
+	pool := make([]*Generator, 1<<nodeBits)
+	for i := range pool {
+		node := uint64(i) //nolint:gosec
+		g, err := newGenerator(&seed, rand.Reader, []Option{func(g *Generator) error {
+			g.layout = layout
+			g.node = node
+			return nil
+		}})
+		if err != nil {
+			panic(err)
+		}
+		pool[i] = g
+	}
+
+	// hints remembers, per P, which generator last served a caller, so a
+	// goroutine tends to keep reusing the same uncontended generator.
+	var next atomic.Uint64
+	hints := sync.Pool{New: func() any {
+		h := next.Add(1)
+		return &h
+	}}
+
+	n := uint64(len(pool))
+	return func() ID {
+		hint := hints.Get().(*uint64)
+		defer hints.Put(hint)
+
+		for i := uint64(0); i < n; i++ {
+			slot := (*hint + i) % n
+			if id, ok := pool[slot].tryGenerate(); ok {
+				*hint = slot
+				return id
+			}
+		}
+		return pool[*hint%n].Generate()
+	}
+}
+
+// tryGenerate creates a unique ID like Generate, but only if the generator is
+// not in use by another goroutine and not paused.
+func (g *Generator) tryGenerate() (ID, bool) {
+	if !g.mu.TryLock() {
+		return 0, false
+	}
+	defer g.mu.Unlock()
+
+	if g.closed {
+		panic(ErrClosed)
+	}
+	if g.paused != nil {
+		return 0, false
+	}
+
+	now := g.currentMillis()
+	g.observeClock(now)
+	id, err := g.nextChecked(now, g.node)
+	if err != nil {
+		panic(err)
+	}
+	return id, true
+}
@@ -0,0 +1,67 @@
+package crystal
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestGoroutineLocal(t *testing.T) {
+	const (
+		goroutines = 64
+		perG       = 2000
+	)
+	generate := GoroutineLocal(4)
+
+	var (
+		mu   sync.Mutex
+		seen = make(map[ID]bool, goroutines*perG)
+		wg   sync.WaitGroup
+	)
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids := make([]ID, perG)
+			for j := range ids {
+				ids[j] = generate()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, id := range ids {
+				if seen[id] {
+					t.Errorf("duplicate ID %d", id)
+				}
+				seen[id] = true
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != goroutines*perG {
+		t.Fatalf("got %d unique IDs, want %d", len(seen), goroutines*perG)
+	}
+}
+
+func TestGoroutineLocalInvalid(t *testing.T) {
+	for _, bits := range []uint{0, 17} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("GoroutineLocal(%d) should panic", bits)
+				}
+			}()
+			GoroutineLocal(bits)
+		}()
+	}
+}
+
+func BenchmarkGoroutineLocal(b *testing.B) {
+	generate := GoroutineLocal(6)
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = generate()
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package crystal
+
+import "time"
+
+const (
+	// rolloverWarnThreshold is how long a rollover wait may take before it is
+	// reported to the generator's Logger.
+	rolloverWarnThreshold = time.Millisecond
+	// rolloverWarnInterval limits rollover warnings to one per interval.
+	rolloverWarnInterval = time.Second
+)
+
+// Logger receives warnings from a Generator. Its signature matches the Warn
+// method of *slog.Logger, so a slog logger can be passed directly.
+type Logger interface {
+	Warn(msg string, kv ...any)
+}
+
+// WithLogger makes the generator report slow sequence rollovers to l: when
+// the sequence is exhausted and waiting for the clock to advance takes longer
+// than a millisecond, a warning is logged, at most once per second. Nothing is
+// logged or measured while IDs are generated without waiting.
+func WithLogger(l Logger) Option {
+	return func(g *Generator) error {
+		g.logger = l
+		return nil
+	}
+}
+
+// logRolloverWait reports a rollover wait that began at start, subject to the
+// threshold and rate limit. The caller must hold the lock.
+func (g *Generator) logRolloverWait(start time.Time) {
+	now := time.Now()
+	wait := now.Sub(start)
+	if wait <= rolloverWarnThreshold {
+		return
+	}
+	if !g.lastWarn.IsZero() && now.Sub(g.lastWarn) < rolloverWarnInterval {
+		return
+	}
+	g.lastWarn = now
+	g.logger.Warn("crystal: slow sequence rollover", "wait", wait, "millis", g.lastMillis)
+}
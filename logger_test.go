@@ -0,0 +1,82 @@
+package crystal
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingLogger struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (l *recordingLogger) Warn(msg string, _ ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.msgs = append(l.msgs, msg)
+}
+
+func (l *recordingLogger) count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.msgs)
+}
+
+func TestWithLoggerSlowRollover(t *testing.T) {
+	origTimebits := Timebits
+	t.Cleanup(func() {
+		Timebits = origTimebits
+	})
+
+	// The smallest step space makes rollovers cheap to reach.
+	Timebits = maxTimebits
+
+	clock := newManualClock(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	logger := &recordingLogger{}
+	gen := New(WithClock(clock), WithLogger(logger))
+
+	// Exhaust the sequence without a rollover: no warning on the fast path.
+	for gen.RemainingThisMillis() > 0 {
+		gen.Generate()
+	}
+	if n := logger.count(); n != 0 {
+		t.Fatalf("expected no warnings before rollover, got %d", n)
+	}
+
+	// The next ID has to wait for the clock, which is held back long enough
+	// to cross the threshold.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		time.Sleep(5 * rolloverWarnThreshold)
+		clock.Add(time.Millisecond)
+	}()
+	gen.Generate()
+	<-done
+
+	if n := logger.count(); n != 1 {
+		t.Fatalf("expected 1 warning after slow rollover, got %d", n)
+	}
+
+	// A second slow rollover within the interval is suppressed.
+	for gen.RemainingThisMillis() > 0 {
+		gen.Generate()
+	}
+	go func() {
+		time.Sleep(5 * rolloverWarnThreshold)
+		clock.Add(time.Millisecond)
+	}()
+	gen.Generate()
+
+	if n := logger.count(); n != 1 {
+		t.Fatalf("expected rate-limited warnings, got %d", n)
+	}
+}
+
+func TestGenerateNilLoggerNoAlloc(t *testing.T) {
+	gen := New()
+	if allocs := testing.AllocsPerRun(1000, func() { gen.Generate() }); allocs != 0 {
+		t.Fatalf("Generate allocated %v times per call", allocs)
+	}
+}
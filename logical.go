@@ -0,0 +1,23 @@
+package crystal
+
+import "time"
+
+// AdvanceTo moves the generator onto a logical clock set to millis
+// (milliseconds since the Unix epoch), e.g. the timestamp of the event being
+// replayed. From then on Generate stamps IDs with the logical time instead
+// of reading the clock, until AdvanceTo is called again; calls that would
+// move the logical clock backwards are ignored. When the sequence of the
+// logical millisecond is exhausted the generator borrows the following
+// millisecond rather than waiting. Together with NewWithSeed and an injected
+// clock this makes replays deterministic.
+func (g *Generator) AdvanceTo(millis int64) {
+	ticks := max(0, g.layout.ticksSince(time.UnixMilli(millis)))
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !g.logicalSet || ticks > g.logical {
+		g.logical = ticks
+		g.logicalSet = true
+	}
+}
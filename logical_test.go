@@ -0,0 +1,33 @@
+package crystal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdvanceTo(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newManualClock(start)
+	gen := New(WithClock(clock))
+
+	event := start.Add(90 * time.Minute)
+	gen.AdvanceTo(event.UnixMilli())
+	a := gen.Generate()
+	if !a.Time().Equal(event) {
+		t.Fatalf("Time() = %v, want %v", a.Time(), event)
+	}
+
+	// The wall clock no longer matters, and the logical clock never goes back.
+	clock.Add(3 * time.Hour)
+	gen.AdvanceTo(start.UnixMilli())
+	b := gen.Generate()
+	if !b.Time().Equal(event) || b <= a {
+		t.Fatalf("Time() = %v (ID %d after %d), want %v", b.Time(), b, a, event)
+	}
+
+	later := event.Add(time.Second)
+	gen.AdvanceTo(later.UnixMilli())
+	if c := gen.Generate(); !c.Time().Equal(later) || c <= b {
+		t.Fatalf("Time() = %v after advancing, want %v", c.Time(), later)
+	}
+}
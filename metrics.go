@@ -0,0 +1,77 @@
+package crystal
+
+import "time"
+
+// MetricsSnapshot is a point-in-time copy of a generator's counters, suitable
+// for exposing on a debug endpoint.
+type MetricsSnapshot struct {
+	// Generated counts IDs issued by Generate and its variants (GenerateRange
+	// and GenerateShort are not included).
+	Generated uint64 `json:"generated"`
+	// Rollovers counts how often the sequence of a millisecond was exhausted.
+	Rollovers uint64 `json:"rollovers"`
+	// ClockBackwards counts how often the clock was seen jumping backwards.
+	ClockBackwards uint64 `json:"clockBackwards"`
+	// MaxBackwardDrift is the largest backward jump (see MaxBackwardDrift).
+	MaxBackwardDrift time.Duration `json:"maxBackwardDrift"`
+	// RemainingThisMillis is the value RemainingThisMillis would return.
+	RemainingThisMillis uint64 `json:"remainingThisMillis"`
+}
+
+// MetricsSnapshot returns a consistent copy of the generator's counters. It
+// does not allocate and is safe to call concurrently with Generate.
+func (g *Generator) MetricsSnapshot() MetricsSnapshot {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return MetricsSnapshot{
+		Generated:           g.generated.Load(),
+		Rollovers:           g.rollovers.Load(),
+		ClockBackwards:      g.clockBackwards.Load(),
+		MaxBackwardDrift:    g.MaxBackwardDrift(),
+		RemainingThisMillis: g.layout.stepMask() - g.step,
+	}
+}
+
+// Stats holds a generator's monotonic counters for export to metrics
+// systems. Fields are only ever added, so collectors (such as a Prometheus
+// collector in a separate module, which keeps the core dependency-free) can
+// rely on them across releases.
+type Stats struct {
+	// Generated, Rollovers and ClockBackwards are as in MetricsSnapshot.
+	Generated      uint64
+	Rollovers      uint64
+	ClockBackwards uint64
+	// RandFailures is the value RandFailures would return.
+	RandFailures uint64
+	// MaxBackwardDrift is the largest backward clock jump observed.
+	MaxBackwardDrift time.Duration
+}
+
+// ReadStats fills dst with the generator's counters. Unlike MetricsSnapshot
+// it takes no lock: each counter is read atomically, though not all at the
+// same instant, so it never delays Generate and suits frequent scrapes.
+// Passing a caller-owned dst lets collectors reuse one Stats across scrapes.
+func (g *Generator) ReadStats(dst *Stats) {
+	dst.Generated = g.generated.Load()
+	dst.Rollovers = g.rollovers.Load()
+	dst.ClockBackwards = g.clockBackwards.Load()
+	dst.RandFailures = g.randFailures.Load()
+	dst.MaxBackwardDrift = g.MaxBackwardDrift()
+}
+
+// Saturation returns how far into the step space the last ID landed: 0 at the
+// bottom of a millisecond's sequence and 1 when the next ID in the same
+// millisecond would roll over. Sequences start at a random step in the lower
+// half, so a clock-bound generator reads at most about 0.5 while values near
+// 1 mean it is step-bound and would benefit from more step bits. Before the
+// first ID it returns 0.
+func (g *Generator) Saturation() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.generated.Load() == 0 {
+		return 0
+	}
+	return float64(g.step) / float64(g.layout.stepMask())
+}
@@ -0,0 +1,152 @@
+package crystal
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMetricsSnapshot(t *testing.T) {
+	gen := New()
+
+	const (
+		goroutines = 8
+		perG       = 20_000
+	)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perG; j++ {
+				gen.Generate()
+				if j%1000 == 0 {
+					_ = gen.MetricsSnapshot()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	m := gen.MetricsSnapshot()
+	if m.Generated != goroutines*perG {
+		t.Errorf("Generated = %d, want %d", m.Generated, goroutines*perG)
+	}
+	if m.RemainingThisMillis > gen.Layout().stepMask() {
+		t.Errorf("RemainingThisMillis = %d exceeds step mask", m.RemainingThisMillis)
+	}
+	if m.ClockBackwards != 0 || m.MaxBackwardDrift != 0 {
+		t.Errorf("unexpected clock trouble: %+v", m)
+	}
+}
+
+func TestMetricsSnapshotClockBackwards(t *testing.T) {
+	origTimebits := Timebits
+	t.Cleanup(func() {
+		Timebits = origTimebits
+	})
+
+	// The smallest step space makes rollovers cheap to reach.
+	Timebits = maxTimebits
+
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newManualClock(start)
+	gen := New(WithClock(clock))
+
+	gen.Generate()
+	clock.Add(-50 * time.Millisecond)
+	gen.Generate()
+	gen.Generate() // still behind: same jump
+	clock.Set(start.Add(time.Millisecond))
+	gen.Generate()
+	clock.Add(-10 * time.Millisecond)
+
+	// While behind the clock, exhausting the sequence borrows the next
+	// millisecond instead of waiting.
+	n := gen.Layout().stepMask() + 1
+	for i := uint64(0); i < n; i++ {
+		gen.Generate()
+	}
+
+	m := gen.MetricsSnapshot()
+	if m.ClockBackwards != 2 {
+		t.Errorf("ClockBackwards = %d, want 2", m.ClockBackwards)
+	}
+	if m.MaxBackwardDrift != 50*time.Millisecond {
+		t.Errorf("MaxBackwardDrift = %v, want 50ms", m.MaxBackwardDrift)
+	}
+	if m.Generated != 4+n {
+		t.Errorf("Generated = %d, want %d", m.Generated, 4+n)
+	}
+	if m.Rollovers != 1 {
+		t.Errorf("Rollovers = %d, want 1", m.Rollovers)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { _ = gen.MetricsSnapshot() }); allocs != 0 {
+		t.Errorf("MetricsSnapshot allocated %v times per call", allocs)
+	}
+}
+
+func TestReadStats(t *testing.T) {
+	clock := newManualClock(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	gen := New(WithClock(clock))
+
+	var prev, cur Stats
+	gen.ReadStats(&prev)
+	for round := 0; round < 5; round++ {
+		for i := 0; i < 100; i++ {
+			gen.Generate()
+		}
+		clock.Add(time.Millisecond)
+
+		gen.ReadStats(&cur)
+		if cur.Generated != prev.Generated+100 {
+			t.Fatalf("Generated = %d, want %d", cur.Generated, prev.Generated+100)
+		}
+		if cur.Rollovers < prev.Rollovers || cur.ClockBackwards < prev.ClockBackwards ||
+			cur.RandFailures < prev.RandFailures || cur.MaxBackwardDrift < prev.MaxBackwardDrift {
+			t.Fatalf("counters went backwards: %+v after %+v", cur, prev)
+		}
+		prev = cur
+	}
+
+	// The last reading was 4ms in; this goes back to 0ms.
+	clock.Add(-5 * time.Millisecond)
+	gen.Generate()
+	gen.ReadStats(&cur)
+	if cur.ClockBackwards != prev.ClockBackwards+1 || cur.MaxBackwardDrift != 4*time.Millisecond {
+		t.Fatalf("after a backward jump: %+v", cur)
+	}
+}
+
+func TestSaturation(t *testing.T) {
+	origTimebits, origNodebits := Timebits, Nodebits
+	t.Cleanup(func() {
+		Timebits, Nodebits = origTimebits, origNodebits
+	})
+	Timebits, Nodebits = maxTimebits, 9 // 63 - 48 - 9 = 6 step bits
+
+	gen := New(WithClock(newManualClock(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))))
+	if s := gen.Saturation(); s != 0 {
+		t.Fatalf("Saturation before generating = %v, want 0", s)
+	}
+
+	gen.Generate()
+	first := gen.Saturation()
+	l := gen.Layout()
+	if limit := float64(l.stepSeedMask()+1) / float64(l.stepMask()); first > limit {
+		t.Fatalf("Saturation after one ID = %v, want at most %v", first, limit)
+	}
+
+	prev := first
+	for gen.Saturation() < 1 {
+		gen.Generate()
+		s := gen.Saturation()
+		if s <= prev {
+			t.Fatalf("Saturation went from %v to %v", prev, s)
+		}
+		prev = s
+	}
+	if prev != 1 {
+		t.Fatalf("Saturation ended at %v, want 1", prev)
+	}
+}
@@ -0,0 +1,44 @@
+package crystal
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// FromObjectID maps a MongoDB ObjectID (4-byte big-endian Unix seconds, 5
+// random bytes, 3-byte counter) onto an ID under layout l, deterministically.
+// The timestamp is preserved at second precision; the remaining 64 bits are
+// XOR-folded into the node and sequence bits, which cannot hold them all, so
+// distinct ObjectIDs from the same second can map to the same ID (rarely for
+// layouts with many sequence bits) and the original cannot be recovered. It
+// returns an error wrapping ErrOutOfRange if the timestamp lies before l's
+// epoch or beyond its time bits.
+func FromObjectID(oid [12]byte, l Layout) (ID, error) {
+	sec := int64(binary.BigEndian.Uint32(oid[:4]))
+	ticks := l.ticksSince(time.Unix(sec, 0))
+	if ticks < 0 || ticks != l.clampMillis(ticks) {
+		return 0, fmt.Errorf("%w: ObjectID time %s does not fit the layout",
+			ErrOutOfRange, time.Unix(sec, 0).UTC().Format(time.RFC3339))
+	}
+
+	width := uint(l.nodebits() + l.stepBits())
+	mask := uint64(1)<<width - 1
+	var folded uint64
+	for rest := binary.BigEndian.Uint64(oid[4:]); rest != 0; rest >>= width {
+		folded ^= rest & mask
+	}
+	return l.compose(ticks, folded>>uint(l.stepBits()), folded&l.stepMask()), nil
+}
+
+// ObjectID returns a MongoDB ObjectID carrying the ID's timestamp, truncated
+// to whole seconds, followed by the ID's 8 bytes in place of the random and
+// counter fields. ObjectIDs built this way are unique and sort like the IDs,
+// but FromObjectID does not invert ObjectID: it folds those 8 bytes into the
+// sequence and yields a different ID.
+func (id ID) ObjectID() [12]byte {
+	var oid [12]byte
+	binary.BigEndian.PutUint32(oid[:4], uint32(id.Time().Unix())) //nolint:gosec
+	binary.BigEndian.PutUint64(oid[4:], id.Uint64())
+	return oid
+}
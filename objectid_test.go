@@ -0,0 +1,61 @@
+package crystal
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFromObjectID(t *testing.T) {
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	var oid [12]byte
+	binary.BigEndian.PutUint32(oid[:4], uint32(at.Unix()))
+	copy(oid[4:], []byte{0x12, 0x34, 0x56, 0x78, 0x9a, 0x00, 0x00, 0x2a})
+
+	l := DefaultLayout()
+	id, err := FromObjectID(oid, l)
+	if err != nil {
+		t.Fatalf("FromObjectID() failed: %v", err)
+	}
+	if !id.Time().Equal(at) {
+		t.Fatalf("FromObjectID().Time() = %v, want %v", id.Time(), at)
+	}
+
+	again, _ := FromObjectID(oid, l)
+	if again != id {
+		t.Fatalf("FromObjectID() not deterministic: %d then %d", id, again)
+	}
+	oid[11]++
+	if next, _ := FromObjectID(oid, l); next == id {
+		t.Fatal("FromObjectID() ignored the counter")
+	}
+
+	var early [12]byte
+	binary.BigEndian.PutUint32(early[:4], uint32(time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC).Unix()))
+	if _, err := FromObjectID(early, l); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("FromObjectID(2015) error = %v, want ErrOutOfRange", err)
+	}
+}
+
+func TestObjectID(t *testing.T) {
+	at := time.Date(2024, 6, 1, 12, 0, 0, 750_000_000, time.UTC)
+	gen := New(WithClock(newManualClock(at)))
+	a, b := gen.Generate(), gen.Generate()
+
+	oa, ob := a.ObjectID(), b.ObjectID()
+	if sec := binary.BigEndian.Uint32(oa[:4]); int64(sec) != at.Unix() {
+		t.Fatalf("ObjectID() seconds = %d, want %d", sec, at.Unix())
+	}
+	if string(oa[:]) >= string(ob[:]) {
+		t.Fatal("ObjectIDs do not sort like their IDs")
+	}
+
+	back, err := FromObjectID(oa, DefaultLayout())
+	if err != nil {
+		t.Fatalf("FromObjectID(ObjectID()) failed: %v", err)
+	}
+	if !back.Time().Equal(at.Truncate(time.Second)) {
+		t.Fatalf("round-trip time = %v, want %v", back.Time(), at.Truncate(time.Second))
+	}
+}
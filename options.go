@@ -0,0 +1,161 @@
+package crystal
+
+import (
+	"fmt"
+	"hash"
+	mrand "math/rand/v2"
+	"time"
+)
+
+// Option configures a Generator at construction time.
+type Option func(*Generator) error
+
+// WithClock makes the generator read the current time from c instead of the
+// system clock. It is mainly useful for tests that need to control time.
+func WithClock(c Clock) Option {
+	return func(g *Generator) error {
+		if c == nil {
+			return fmt.Errorf("clock must not be nil")
+		}
+		g.clock = c
+		return nil
+	}
+}
+
+// WithNode stamps every ID the generator creates with the given node
+// identifier. The node must fit in the layout's Nodebits.
+func WithNode(node uint16) Option {
+	return func(g *Generator) error {
+		g.node = uint64(node)
+		return nil
+	}
+}
+
+// WithTenantSalt mixes salt into every counter seed the generator derives, so
+// generators for different tenants start their sequences at unrelated
+// positions even when they are created from the same seed at the same instant.
+//
+// Salting only changes where a sequence starts, not how large the step space
+// is: tenants that share a generator (or that sit behind the same node) still
+// share its per-millisecond capacity.
+func WithTenantSalt(salt []byte) Option {
+	return func(g *Generator) error {
+		g.salt = append([]byte(nil), salt...)
+		return nil
+	}
+}
+
+// WithReservedWindow makes the generator reserve the given window of future
+// milliseconds. Once the sequence for the current millisecond is exhausted the
+// generator moves on to the next millisecond inside the window instead of
+// busy-waiting for the wall clock, and a background goroutine keeps the window
+// topped up as real time passes. Call Close to stop the goroutine once the
+// generator is no longer needed.
+//
+// IDs remain unique and monotonically increasing, but their embedded
+// timestamps may run ahead of real time by at most the window size; the one
+// exception is GenerateAfter with a future watermark, which borrows
+// milliseconds beyond the window to stay above it. Generate
+// only waits when callers consume the whole window faster than the clock
+// refills it, i.e. when more than window × 2^stepBits IDs are issued ahead of
+// the clock.
+func WithReservedWindow(window time.Duration) Option {
+	return func(g *Generator) error {
+		if window < time.Millisecond {
+			return fmt.Errorf("reserved window must be at least 1ms: %s", window)
+		}
+		g.reserve = &reservation{span: window}
+		return nil
+	}
+}
+
+// WithFastReseed makes the generator draw the starting sequence value of each
+// millisecond from a PCG generator (math/rand/v2) instead of hashing fresh
+// cryptographic randomness, removing the dominant cost of a new millisecond.
+//
+// The PCG is seeded once from the host digest, the salt and the entropy
+// source, but its output is not cryptographically secure: anyone who sees
+// enough IDs can predict future starting values. Use it for benchmarks or
+// where unpredictability of IDs does not matter. SetEntropy has no effect on
+// per-millisecond seeds of such a generator.
+func WithFastReseed() Option {
+	return func(g *Generator) error {
+		g.fast = &mrand.PCG{}
+		return nil
+	}
+}
+
+// WithStrictEpoch makes the generator refuse to issue IDs while its clock
+// reads earlier than the epoch: GenerateSafe returns ErrBeforeEpoch (and
+// Generate panics with it) instead of stamping IDs with timestamp zero. This
+// catches epochs that were accidentally configured in the future.
+func WithStrictEpoch() Option {
+	return func(g *Generator) error {
+		g.strictEpoch = true
+		return nil
+	}
+}
+
+// WithScatteredTime stores the timestamp with its bits reversed, so that IDs
+// from consecutive milliseconds land far apart in the key space and inserts
+// spread across a B-tree instead of piling up at its right edge. The
+// generator's Layout (ScatteredTime set) still decodes the time correctly,
+// whereas the package-level ID methods, which use DefaultLayout, do not.
+//
+// The price is ordering: IDs no longer sort by time, neither numerically nor
+// in any of their string encodings, and only IDs from the same millisecond
+// stay in generation order. Comparisons such as Less and GenerateAfter follow
+// numeric order and lose their time meaning.
+func WithScatteredTime() Option {
+	return func(g *Generator) error {
+		g.layout.ScatteredTime = true
+		return nil
+	}
+}
+
+// WithKind stamps every ID with the entity-kind tag kind in a field of
+// kindBits bits (at most 8) between the timestamp and the node, carved out of
+// the sequence space, so an ID reveals which kind of entity it names. Decode
+// the tag with the generator's Layout, or set the package-level Kindbits to
+// match before using ID.Kind.
+func WithKind(kind uint8, kindBits uint) Option {
+	return func(g *Generator) error {
+		if kindBits == 0 || kindBits > maxKindbits {
+			return fmt.Errorf("kind bits must be between 1 and %d: %d", maxKindbits, kindBits)
+		}
+		if uint(kind) >= 1<<kindBits {
+			return fmt.Errorf("kind %d does not fit in %d bits", kind, kindBits)
+		}
+		g.layout.Kindbits = int(kindBits)
+		g.kind = uint64(kind)
+		return nil
+	}
+}
+
+// WithLayout replaces the generator's layout, which otherwise is a snapshot of
+// the package-level configuration (DefaultLayout). Options that adjust the
+// layout, such as WithFullWidth or WithKind, apply on top of it when they come
+// later.
+func WithLayout(l Layout) Option {
+	return func(g *Generator) error {
+		g.layout = l
+		return nil
+	}
+}
+
+// WithSeedHash makes the generator hash its host seed (hostname, PID and
+// process start time) with hashes from newHash instead of SHA-256, e.g.
+// fnv.New128a to shave startup time off short-lived processes. The seed only
+// needs to differ between processes, not to resist attackers, and the counter
+// seeds derived from it are still mixed with cryptographic randomness. Sums
+// shorter than the 32-byte seed are zero-padded, longer ones truncated. It has
+// no effect on NewWithSeed, whose seed is explicit.
+func WithSeedHash(newHash func() hash.Hash) Option {
+	return func(g *Generator) error {
+		if newHash == nil {
+			return fmt.Errorf("seed hash must not be nil")
+		}
+		g.seedHash = newHash
+		return nil
+	}
+}
@@ -0,0 +1,51 @@
+package crystal
+
+import "encoding/base32"
+
+// base32Decode maps each byte to its value in base32Alphabet, or 0xff.
+//
+//nolint:gochecknoglobals
+var base32Decode = func() (t [256]byte) {
+	for i := range t {
+		t[i] = 0xff
+	}
+	for i := 0; i < len(base32Alphabet); i++ {
+		t[base32Alphabet[i]] = byte(i)
+	}
+	return t
+}()
+
+// ParseBase32Bytes is ParseBase32 for a byte slice, e.g. one read straight
+// off the network. It accepts exactly what ParseBase32 accepts, including
+// the embedded line breaks encoding/base32 skips, and does not allocate
+// unless it fails.
+func ParseBase32Bytes(b []byte) (ID, error) {
+	var v uint64
+	n := 0
+	for i, c := range b {
+		if c == '\r' || c == '\n' {
+			continue
+		}
+		d := base32Decode[c]
+		if d == 0xff || n == base32Len {
+			return 0, base32.CorruptInputError(i)
+		}
+		if n == base32Len-1 {
+			// The last character holds the final 4 bits and a pad bit.
+			v = v<<4 | uint64(d>>1)
+		} else {
+			v = v<<5 | uint64(d)
+		}
+		n++
+	}
+	if n != base32Len {
+		return 0, base32.CorruptInputError(len(b))
+	}
+	return ID(v), nil //nolint:gosec
+}
+
+// ParseHexBytes is ParseHex for a byte slice. It accepts exactly what
+// ParseHex accepts and does not allocate unless it fails.
+func ParseHexBytes(b []byte) (ID, error) {
+	return parseHex(b)
+}
@@ -0,0 +1,73 @@
+package crystal
+
+import (
+	"testing"
+)
+
+func TestParseBytesMatchesString(t *testing.T) {
+	gen := New()
+	inputs := []string{"", "0", "zzzzzzzzzzzzz", "0000000000001", "0D6AV3W2KC002", "0d6av3w2kc00", "0d6av3w2kc0022"}
+	hexInputs := []string{"", "0", "00ff11aa22bb33c", "00FF11AA22BB33CC", "00ff11aa22bb33zz", "00ff11aa22bb33cc00"}
+	for i := 0; i < 50; i++ {
+		id := gen.Generate()
+		s := id.Base32()
+		inputs = append(inputs, s, s[:5]+"\n"+s[5:], s[:12]+"\r\n"+s[12:], s+"\n", "\n"+s, s[:6]+"\n")
+		hexInputs = append(hexInputs, id.Hex())
+	}
+
+	for _, s := range inputs {
+		want, wantErr := ParseBase32(s)
+		got, err := ParseBase32Bytes([]byte(s))
+		if got != want || (err == nil) != (wantErr == nil) {
+			t.Errorf("ParseBase32Bytes(%q) = %d, %v; ParseBase32 = %d, %v", s, got, err, want, wantErr)
+		}
+	}
+	id := gen.Generate()
+	wrapped := id.Base32()[:5] + "\n" + id.Base32()[5:]
+	if got, err := ParseBase32Bytes([]byte(wrapped)); err != nil || got != id {
+		t.Errorf("ParseBase32Bytes(%q) = %d, %v; want %d", wrapped, got, err, id)
+	}
+	for _, s := range hexInputs {
+		want, wantErr := ParseHex(s)
+		got, err := ParseHexBytes([]byte(s))
+		if got != want || (err == nil) != (wantErr == nil) {
+			t.Errorf("ParseHexBytes(%q) = %d, %v; ParseHex = %d, %v", s, got, err, want, wantErr)
+		}
+	}
+}
+
+func TestParseBytesAllocs(t *testing.T) {
+	id := New().Generate()
+	b32, hx := []byte(id.Base32()), []byte(id.Hex())
+
+	bytesAllocs := testing.AllocsPerRun(100, func() {
+		_, _ = ParseBase32Bytes(b32)
+		_, _ = ParseHexBytes(hx)
+	})
+	stringAllocs := testing.AllocsPerRun(100, func() {
+		_, _ = ParseBase32(string(b32))
+		_, _ = ParseHex(string(hx))
+	})
+	if bytesAllocs != 0 {
+		t.Errorf("byte parsers allocated %.0f times, want 0", bytesAllocs)
+	}
+	if bytesAllocs >= stringAllocs {
+		t.Errorf("byte parsers allocated %.0f times, string parsers %.0f", bytesAllocs, stringAllocs)
+	}
+}
+
+func BenchmarkParseBase32Bytes(b *testing.B) {
+	s := []byte(New().Generate().Base32())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseBase32Bytes(s)
+	}
+}
+
+func BenchmarkParseBase32(b *testing.B) {
+	s := []byte(New().Generate().Base32())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseBase32(string(s))
+	}
+}
@@ -0,0 +1,95 @@
+package crystal
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrPaused is returned by GenerateSafe and GenerateForNode while the
+// generator is paused (see Pause).
+var ErrPaused = errors.New("crystal: generator is paused")
+
+// Pause stops the generator from issuing IDs until Resume is called, e.g.
+// while leadership is handed over during a failover. Generate, GenerateU and
+// GenerateAfter block until then, GenerateContext blocks until then or until
+// its context is done, and GenerateSafe and GenerateForNode return ErrPaused.
+// Closing a paused generator releases blocked callers with ErrClosed. Pausing
+// a paused generator has no effect.
+func (g *Generator) Pause() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.paused == nil {
+		g.paused = make(chan struct{})
+	}
+}
+
+// Resume lets a paused generator issue IDs again and wakes callers blocked by
+// Pause. The sequence continues where it stopped, so IDs stay monotonic
+// across the pause. Resuming a generator that is not paused has no effect.
+func (g *Generator) Resume() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.resumeLocked()
+}
+
+// Paused reports whether the generator is paused.
+func (g *Generator) Paused() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.paused != nil
+}
+
+// resumeLocked clears the pause and wakes its waiters. It must be called
+// with g.mu held.
+func (g *Generator) resumeLocked() {
+	if g.paused != nil {
+		close(g.paused)
+		g.paused = nil
+	}
+}
+
+// generateWait is generateContext, waiting out a pause instead of returning
+// ErrPaused.
+func (g *Generator) generateWait(ctx context.Context, node uint64) (ID, error) {
+	for {
+		id, err := g.generateContext(ctx, node)
+		if !errors.Is(err, ErrPaused) {
+			return id, err
+		}
+		if err := g.waitResume(ctx); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// lockUnpaused acquires g.mu once the generator is not paused.
+func (g *Generator) lockUnpaused() {
+	for {
+		g.mu.Lock()
+		if g.paused == nil {
+			return
+		}
+		g.mu.Unlock()
+		_ = g.waitResume(context.Background())
+	}
+}
+
+// waitResume blocks until the current pause, if any, ends or ctx is done.
+func (g *Generator) waitResume(ctx context.Context) error {
+	g.mu.Lock()
+	resumed := g.paused
+	g.mu.Unlock()
+
+	if resumed == nil {
+		return nil
+	}
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
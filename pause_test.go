@@ -0,0 +1,130 @@
+package crystal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPauseResume(t *testing.T) {
+	gen := New()
+	before := gen.Generate()
+
+	gen.Pause()
+	if !gen.Paused() {
+		t.Fatal("Paused() = false after Pause")
+	}
+	if _, err := gen.GenerateSafe(); !errors.Is(err, ErrPaused) {
+		t.Fatalf("GenerateSafe() error = %v, want ErrPaused", err)
+	}
+
+	var issued atomic.Int64
+	var wg sync.WaitGroup
+	ids := make([][]ID, 4)
+	for w := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				ids[w] = append(ids[w], gen.Generate())
+				issued.Add(1)
+			}
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if n := issued.Load(); n != 0 {
+		t.Fatalf("%d IDs issued while paused", n)
+	}
+
+	gen.Resume()
+	wg.Wait()
+	if gen.Paused() {
+		t.Fatal("Paused() = true after Resume")
+	}
+
+	for w := range ids {
+		last := before
+		for _, id := range ids[w] {
+			if id <= last {
+				t.Fatalf("worker %d: ID %d not above %d", w, id, last)
+			}
+			last = id
+		}
+	}
+}
+
+func TestPauseGenerateContext(t *testing.T) {
+	gen := New()
+	gen.Pause()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := gen.GenerateContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GenerateContext() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestPauseClose(t *testing.T) {
+	gen := New()
+	gen.Pause()
+
+	done := make(chan any)
+	go func() {
+		defer func() { done <- recover() }()
+		gen.Generate()
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	_ = gen.Close()
+
+	select {
+	case r := <-done:
+		if err, ok := r.(error); !ok || !errors.Is(err, ErrClosed) {
+			t.Fatalf("Generate() panicked with %v, want ErrClosed", r)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Generate() still blocked after Close")
+	}
+}
+
+func TestPauseRingGenerator(t *testing.T) {
+	gen := New()
+	defer gen.Close()
+	ring := gen.RingGenerator(4)
+
+	take := func() ID {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for time.Now().Before(deadline) {
+			if id, ok := ring.TryGet(); ok {
+				return id
+			}
+			time.Sleep(time.Millisecond)
+		}
+		t.Fatal("ring stayed empty")
+		return 0
+	}
+
+	before := take()
+	gen.Pause()
+	// Drain what was buffered before the pause took hold.
+	time.Sleep(10 * time.Millisecond)
+	for {
+		if _, ok := ring.TryGet(); !ok {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	if id, ok := ring.TryGet(); ok {
+		t.Fatalf("ring handed out %d while paused", id)
+	}
+
+	gen.Resume()
+	if after := take(); after <= before {
+		t.Fatalf("ID %d after resume, want above %d", after, before)
+	}
+}
@@ -0,0 +1,146 @@
+package crystal
+
+import (
+	"fmt"
+	"time"
+)
+
+// Precision is the unit of the timestamp field of a Layout.
+type Precision int
+
+// Supported timestamp precisions.
+const (
+	// Millisecond stores milliseconds since the epoch (the default).
+	Millisecond Precision = iota
+	// Microsecond stores microseconds since the epoch. The time horizon
+	// shrinks a thousandfold: 42 time bits cover about 51 days and the
+	// maximum of 48 bits about 8.9 years, so pair it with a recent epoch.
+	Microsecond
+	// Second stores whole seconds since the epoch (see WithSecondResolution).
+	// It also allows time widths down to 32 bits.
+	Second
+)
+
+// String returns the name of the unit.
+func (p Precision) String() string {
+	switch p {
+	case Millisecond:
+		return "millisecond"
+	case Microsecond:
+		return "microsecond"
+	case Second:
+		return "second"
+	default:
+		return fmt.Sprintf("Precision(%d)", int(p))
+	}
+}
+
+// WithPrecision sets the unit of the generator's timestamp field. At
+// Microsecond precision every sequence of 2^stepBits IDs belongs to a single
+// microsecond, which suits workloads that issue IDs steadily rather than in
+// bursts; the layout's time bits then span a much shorter horizon (see
+// Microsecond); NewGenerator fails with an error wrapping ErrOutOfRange when
+// the clock already lies beyond it. Decode such IDs with the generator's
+// Layout, since the package-level ID methods assume milliseconds.
+func WithPrecision(p Precision) Option {
+	return func(g *Generator) error {
+		if p != Millisecond && p != Microsecond && p != Second {
+			return fmt.Errorf("unsupported precision: %s", p)
+		}
+		g.layout.Precision = p
+		return nil
+	}
+}
+
+// WithTimeUnit sets the generator's timestamp granularity as a duration:
+// time.Millisecond (the default), time.Second or time.Microsecond. It is
+// WithPrecision with the matching Precision, so generators in one process
+// can serve domains with different needs; the layout, epoch arithmetic and
+// capacity math all follow the unit. Other durations are rejected.
+func WithTimeUnit(unit time.Duration) Option {
+	return func(g *Generator) error {
+		p, ok := precisionFor(unit)
+		if !ok {
+			return fmt.Errorf("unsupported time unit: %s", unit)
+		}
+		g.layout.Precision = p
+		return nil
+	}
+}
+
+// TimeUnit returns the duration of one unit of the generator's timestamp
+// field (see WithTimeUnit).
+func (g *Generator) TimeUnit() time.Duration {
+	return g.layout.tick()
+}
+
+// precisionFor returns the Precision whose tick is unit.
+func precisionFor(unit time.Duration) (Precision, bool) {
+	switch unit {
+	case time.Millisecond:
+		return Millisecond, true
+	case time.Microsecond:
+		return Microsecond, true
+	case time.Second:
+		return Second, true
+	default:
+		return 0, false
+	}
+}
+
+// WithSecondResolution stores whole seconds since the epoch in a 32-bit
+// timestamp, leaving 31 bits of sequence by default: about 136 years from
+// the epoch, and 2^31 IDs per second instead of 2^21 per millisecond, which
+// suits low-rate systems that value compact, long-lived layouts over
+// sub-second ordering. IDs from the same second sort by issue order only
+// within one generator. Options that follow may widen the timestamp again
+// (e.g. WithLayout replaces it entirely). Decode such IDs with the
+// generator's Layout.
+func WithSecondResolution() Option {
+	return func(g *Generator) error {
+		g.layout.Precision = Second
+		g.layout.Timebits = secondTimebits
+		return nil
+	}
+}
+
+// tick returns the duration of one timestamp unit.
+func (l Layout) tick() time.Duration {
+	switch l.Precision {
+	case Microsecond:
+		return time.Microsecond
+	case Second:
+		return time.Second
+	default:
+		return time.Millisecond
+	}
+}
+
+// ticksSince returns the number of timestamp units between l.Epoch and t,
+// negative when t is earlier.
+func (l Layout) ticksSince(t time.Time) int64 {
+	switch l.Precision {
+	case Microsecond:
+		return t.UnixMicro() - l.Epoch*1000
+	case Second:
+		ms := t.UnixMilli() - l.Epoch
+		if ms < 0 {
+			return (ms - 999) / 1000
+		}
+		return ms / 1000
+	default:
+		return t.UnixMilli() - l.Epoch
+	}
+}
+
+// timeAt returns the instant ticks timestamp units after l.Epoch.
+func (l Layout) timeAt(ticks int64) time.Time {
+	switch l.Precision {
+	case Microsecond:
+		return time.UnixMicro(ticks + l.Epoch*1000)
+	case Second:
+		return time.UnixMilli(ticks*1000 + l.Epoch)
+	default:
+		return time.UnixMilli(ticks + l.Epoch)
+	}
+}
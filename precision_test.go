@@ -0,0 +1,111 @@
+package crystal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithPrecisionMicrosecond(t *testing.T) {
+	// 48 time bits at microsecond precision span about 8.9 years, so count
+	// from a recent epoch.
+	l := DefaultLayout()
+	l.Epoch = time.Now().Add(-time.Hour).UnixMilli()
+	l.Timebits = 48
+	gen := New(WithLayout(l), WithPrecision(Microsecond))
+
+	before := time.Now()
+	id := gen.Generate()
+	after := time.Now()
+
+	got := gen.Layout().Time(id)
+	if got.Before(before.Truncate(time.Microsecond)) || got.After(after) {
+		t.Fatalf("Time() = %v, want between %v and %v", got, before, after)
+	}
+	if gen.Layout().Precision != Microsecond {
+		t.Fatalf("Layout().Precision = %s, want microsecond", gen.Layout().Precision)
+	}
+}
+
+func TestWithPrecisionDecodesUnits(t *testing.T) {
+	ts := time.Date(2024, 6, 1, 12, 0, 0, 123_456_000, time.UTC)
+	l := DefaultLayout()
+	l.Epoch = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC).UnixMilli() // within 51 days
+	milli := New(WithClock(newManualClock(ts)), WithLayout(l))
+	micro := New(WithClock(newManualClock(ts)), WithLayout(l), WithPrecision(Microsecond))
+
+	if got := milli.Layout().Time(milli.Generate()); !got.Equal(ts.Truncate(time.Millisecond)) {
+		t.Errorf("millisecond Time() = %v, want %v", got, ts.Truncate(time.Millisecond))
+	}
+	if got := micro.Layout().Time(micro.Generate()); !got.Equal(ts) {
+		t.Errorf("microsecond Time() = %v, want %v", got, ts)
+	}
+}
+
+func TestWithPrecisionBeyondHorizon(t *testing.T) {
+	// The default 2020 epoch and 41 time bits span only about 25 days of
+	// microseconds.
+	_, err := NewGenerator(WithPrecision(Microsecond))
+	if !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("NewGenerator(WithPrecision(Microsecond)) error = %v, want ErrOutOfRange", err)
+	}
+}
+
+func TestWithPrecisionInvalid(t *testing.T) {
+	if _, err := NewGenerator(WithPrecision(Precision(7))); err == nil {
+		t.Fatal("NewGenerator(WithPrecision(7)) succeeded, want error")
+	}
+}
+
+func TestWithSecondResolution(t *testing.T) {
+	gen := New(WithSecondResolution())
+	l := gen.Layout()
+	if l.timebits() != 32 || l.stepBits() != 31 {
+		t.Fatalf("time/step bits = %d/%d, want 32/31", l.timebits(), l.stepBits())
+	}
+
+	before := time.Now().Truncate(time.Second)
+	id := gen.Generate()
+	after := time.Now()
+
+	got := l.Time(id)
+	if got.Before(before) || got.After(after) || got.Nanosecond() != 0 {
+		t.Fatalf("Time() = %v, want the second of %v", got, before)
+	}
+
+	at := time.Date(2024, 6, 1, 12, 0, 7, 900_000_000, time.UTC)
+	fixed := New(WithClock(newManualClock(at)), WithSecondResolution())
+	a, b := fixed.Generate(), fixed.Generate()
+	if !fixed.Layout().Time(a).Equal(at.Truncate(time.Second)) || b <= a {
+		t.Fatalf("Time() = %v (next %d after %d), want %v", fixed.Layout().Time(a), b, a, at.Truncate(time.Second))
+	}
+}
+
+func TestWithTimeUnit(t *testing.T) {
+	ts := time.Date(2024, 6, 1, 12, 34, 56, 789_000_000, time.UTC)
+	seconds := New(WithClock(newManualClock(ts)), WithTimeUnit(time.Second))
+	millis := New(WithClock(newManualClock(ts)), WithTimeUnit(time.Millisecond))
+
+	if seconds.TimeUnit() != time.Second || millis.TimeUnit() != time.Millisecond {
+		t.Fatalf("TimeUnit() = %v, %v; want 1s, 1ms", seconds.TimeUnit(), millis.TimeUnit())
+	}
+	if New().TimeUnit() != time.Millisecond {
+		t.Fatalf("default TimeUnit() = %v, want 1ms", New().TimeUnit())
+	}
+
+	for i := 0; i < 10; i++ {
+		sid, mid := seconds.Generate(), millis.Generate()
+		if got := seconds.Layout().Time(sid); !got.Equal(ts.Truncate(time.Second)) {
+			t.Fatalf("second-granularity Time() = %v, want %v", got, ts.Truncate(time.Second))
+		}
+		if got := millis.Layout().Time(mid); !got.Equal(ts) {
+			t.Fatalf("millisecond-granularity Time() = %v, want %v", got, ts)
+		}
+	}
+
+	for _, unit := range []time.Duration{0, time.Nanosecond, 10 * time.Millisecond, time.Minute, -time.Second} {
+		if _, err := NewGenerator(WithTimeUnit(unit)); err == nil {
+			t.Errorf("NewGenerator(WithTimeUnit(%v)) succeeded, want error", unit)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package crystal
+
+import "math"
+
+// CollisionProbability estimates the probability that, within a single
+// millisecond, at least two of nodes independent generators issue the same
+// ID when each generates perMillis IDs, using the package-level layout.
+//
+// Each generator's IDs for a millisecond form a run of perMillis consecutive
+// steps starting at a random point in the lower half of the step space, so two
+// generators sharing a node value collide when their runs overlap. The
+// estimate applies the birthday bound over all such pairs. Generators with
+// distinct node values never collide, so the result is 0 when node bits are
+// configured and nodes fits within them.
+func CollisionProbability(nodes int, perMillis int) float64 {
+	if nodes <= 1 || perMillis <= 0 {
+		return 0
+	}
+
+	l := DefaultLayout()
+
+	nodeValues := math.Exp2(float64(l.nodebits()))
+	if float64(nodes) <= nodeValues {
+		return 0
+	}
+
+	// Assume nodes are spread evenly across node values; only generators
+	// sharing a value can collide.
+	perValue := float64(nodes) / nodeValues
+	pairs := nodeValues * perValue * (perValue - 1) / 2
+
+	// Probability that two runs of perMillis steps overlap when their starts
+	// are drawn uniformly from the seed range.
+	seedRange := float64(l.stepSeedMask()) + 1
+	overlap := math.Min(1, (2*float64(perMillis)-1)/seedRange)
+
+	return 1 - math.Exp(-pairs*overlap)
+}
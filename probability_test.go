@@ -0,0 +1,46 @@
+package crystal
+
+import "testing"
+
+func TestCollisionProbabilityNone(t *testing.T) {
+	if p := CollisionProbability(1, 1_000_000); p != 0 {
+		t.Errorf("single node probability = %v, want 0", p)
+	}
+	if p := CollisionProbability(100, 0); p != 0 {
+		t.Errorf("zero rate probability = %v, want 0", p)
+	}
+
+	origNodebits := Nodebits
+	t.Cleanup(func() {
+		Nodebits = origNodebits
+	})
+
+	// Distinct node values never collide.
+	Nodebits = 4
+	if p := CollisionProbability(16, 10_000); p != 0 {
+		t.Errorf("probability with a node value per generator = %v, want 0", p)
+	}
+	if p := CollisionProbability(17, 10_000); p <= 0 {
+		t.Errorf("probability with shared node values = %v, want > 0", p)
+	}
+}
+
+func TestCollisionProbabilityRises(t *testing.T) {
+	low := CollisionProbability(2, 1)
+	if low <= 0 || low > 1e-5 {
+		t.Errorf("light load probability = %v, want tiny but positive", low)
+	}
+
+	prev := low
+	for _, nodes := range []int{4, 16, 64, 256} {
+		p := CollisionProbability(nodes, 1000)
+		if p < prev {
+			t.Errorf("probability for %d nodes = %v, dropped below %v", nodes, p, prev)
+		}
+		prev = p
+	}
+
+	if p := CollisionProbability(1000, 100_000); p < 0.99 || p > 1 {
+		t.Errorf("oversubscribed probability = %v, want close to 1", p)
+	}
+}
@@ -0,0 +1,42 @@
+//go:build linux
+
+package crystal
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+)
+
+// osProcessStart returns the process start time recorded in /proc/self/stat,
+// in clock ticks since boot.
+func osProcessStart() (int64, bool) {
+	data, err := os.ReadFile("/proc/self/stat")
+	if err != nil {
+		return 0, false
+	}
+	return parseProcStat(data)
+}
+
+// parseProcStat extracts the starttime field (the 22nd) from the contents of
+// a /proc/<pid>/stat file. The command name in the second field may contain
+// spaces and parentheses, so fields are counted from its closing parenthesis.
+func parseProcStat(data []byte) (int64, bool) {
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return 0, false
+	}
+
+	// Fields after the command name start with the 3rd (state).
+	fields := bytes.Fields(data[end+1:])
+	const startTimeField = 22 - 3
+	if len(fields) <= startTimeField {
+		return 0, false
+	}
+
+	start, err := strconv.ParseInt(string(fields[startTimeField]), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return start, true
+}
@@ -0,0 +1,25 @@
+//go:build linux
+
+package crystal
+
+import "testing"
+
+func TestParseProcStat(t *testing.T) {
+	stat := []byte("4242 (my (odd) cmd) S 1 4242 4242 0 -1 4194560 120 0 0 0 1 0 0 0 20 0 1 0 987654 10485760 200 " +
+		"18446744073709551615 1 1 0 0 0 0 0 0 0 0 0 0 17 3 0 0 0 0 0\n")
+
+	start, ok := parseProcStat(stat)
+	if !ok || start != 987654 {
+		t.Fatalf("parseProcStat() = %d, %v, want 987654, true", start, ok)
+	}
+
+	if _, ok := parseProcStat([]byte("4242 (cmd) S 1")); ok {
+		t.Fatal("parseProcStat() should fail for truncated input")
+	}
+}
+
+func TestOSProcessStart(t *testing.T) {
+	if _, ok := osProcessStart(); !ok {
+		t.Fatal("expected /proc/self/stat to provide a start time")
+	}
+}
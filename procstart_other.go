@@ -0,0 +1,9 @@
+//go:build !linux
+
+package crystal
+
+// osProcessStart reports that the process start time is unavailable, so the
+// package initialization time is used instead.
+func osProcessStart() (int64, bool) {
+	return 0, false
+}
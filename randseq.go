@@ -0,0 +1,67 @@
+package crystal
+
+import (
+	"encoding/binary"
+	"io"
+	mrand "math/rand/v2"
+	"sync/atomic"
+)
+
+// randomSequence tracks the steps handed out in the current millisecond of a
+// WithRandomSequence generator.
+type randomSequence struct {
+	millis int64
+	seen   map[uint64]struct{}
+}
+
+// WithRandomSequence fills the step field of every ID with fresh bits from
+// the generator's entropy source (crypto/rand unless replaced with
+// SetEntropy) instead of a counter, so consecutive IDs reveal nothing about
+// each other. This gives up ordering within a millisecond: IDs from the same
+// millisecond sort randomly, and only IDs from different milliseconds keep
+// their creation order. The generator remembers the steps it used in the
+// current millisecond and draws again on a collision; once half the step
+// space is taken it moves on to the next millisecond, as a counter would on
+// rollover. GenerateAfter cannot place a random step above min's within
+// min's millisecond, so it moves on to the following millisecond instead.
+func WithRandomSequence() Option {
+	return func(g *Generator) error {
+		g.randSeq = &randomSequence{millis: -1, seen: make(map[uint64]struct{})}
+		return nil
+	}
+}
+
+// randomStep sets g.step to an unused random step for millisecond now,
+// moving to the next millisecond when the current one is half full, and
+// returns the millisecond to stamp. It must be called with g.mu held.
+func (g *Generator) randomStep(now int64) int64 {
+	rs := g.randSeq
+	mask := g.layout.stepMask()
+	if now == rs.millis && uint64(len(rs.seen)) >= mask/2+1 {
+		g.rollovers.Add(1)
+		now = g.nextMillis()
+	}
+	if now != rs.millis {
+		rs.millis = now
+		clear(rs.seen)
+	}
+
+	for {
+		step := randomUint64(g.entropy, &g.randFailures) & mask
+		if _, ok := rs.seen[step]; !ok {
+			rs.seen[step] = struct{}{}
+			g.step = step
+			return now
+		}
+	}
+}
+
+// randomUint64 returns 64 bits from entropy, falling back to math/rand if it
+// fails so generation never stalls. Failed reads are counted in failures.
+func randomUint64(entropy io.Reader, failures *atomic.Uint64) uint64 {
+	var buf [8]byte
+	if !readEntropy(entropy, buf[:], failures) {
+		return mrand.Uint64() //nolint:gosec
+	}
+	return binary.BigEndian.Uint64(buf[:])
+}
@@ -0,0 +1,111 @@
+package crystal
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRandomSequenceUnique(t *testing.T) {
+	gen := New(WithRandomSequence(), WithClock(newManualClock(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))))
+	l := gen.Layout()
+
+	const goroutines, perG = 8, 5000
+	results := make([][]ID, goroutines)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids := make([]ID, perG)
+			for j := range ids {
+				ids[j] = gen.Generate()
+			}
+			results[i] = ids
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[ID]bool, goroutines*perG)
+	descending := 0
+	for _, ids := range results {
+		for j, id := range ids {
+			if seen[id] {
+				t.Fatalf("duplicate ID %d", id)
+			}
+			seen[id] = true
+			if l.millis(id) != l.millis(ids[0]) {
+				t.Fatalf("ID %d left the frozen millisecond", id)
+			}
+			if j > 0 && id < ids[j-1] {
+				descending++
+			}
+		}
+	}
+	if descending == 0 {
+		t.Error("random steps came out in ascending order")
+	}
+}
+
+func TestRandomSequenceEntropy(t *testing.T) {
+	gen := New(WithRandomSequence(), WithClock(newManualClock(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))))
+	gen.SetEntropy(&flakyReader{fails: 1, r: bytes.NewReader(bytes.Repeat([]byte{0x2a}, 64))})
+
+	id := gen.Generate()
+	if want := uint64(0x2a2a2a2a2a2a2a2a) & gen.layout.stepMask(); gen.layout.Step(id) != want {
+		t.Fatalf("step = %#x, want %#x from the generator's entropy", gen.layout.Step(id), want)
+	}
+	if n := gen.RandFailures(); n != 1 {
+		t.Fatalf("RandFailures() = %d, want 1", n)
+	}
+}
+
+func TestRandomSequenceFullMillis(t *testing.T) {
+	origTimebits, origNodebits := Timebits, Nodebits
+	t.Cleanup(func() {
+		Timebits, Nodebits = origTimebits, origNodebits
+	})
+	Timebits, Nodebits = maxTimebits, 9 // 63 - 48 - 9 = 6 step bits
+
+	gen := New(WithRandomSequence())
+	l := gen.Layout()
+
+	perMillis := make(map[int64]int)
+	seen := make(map[ID]bool)
+	var prev ID
+	for i := 0; i < 500; i++ {
+		id := gen.Generate()
+		if seen[id] {
+			t.Fatalf("duplicate ID %d", id)
+		}
+		seen[id] = true
+		if i > 0 && l.millis(id) < l.millis(prev) {
+			t.Fatalf("ID %d is from an earlier millisecond than %d", id, prev)
+		}
+		prev = id
+		perMillis[l.millis(id)]++
+	}
+	for millis, n := range perMillis {
+		if n > 32 {
+			t.Fatalf("%d IDs in millisecond %d, want at most half the step space", n, millis)
+		}
+	}
+}
+
+func TestRandomSequenceGenerateAfter(t *testing.T) {
+	gen := New(WithRandomSequence(), WithClock(newManualClock(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))))
+
+	min := gen.Generate()
+	for i := 0; i < 1000; i++ {
+		id := gen.GenerateAfter(min)
+		if id <= min {
+			t.Fatalf("trial %d: GenerateAfter(%d) = %d, want greater", i, min, id)
+		}
+		if i%2 == 0 {
+			min = id
+		} else {
+			min = gen.Generate()
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package crystal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// tokenBucket paces generation to a fixed rate (see WithRateLimit). It runs on
+// wall time rather than the generator's Clock, since callers really sleep.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// WithRateLimit caps the generator at perSecond IDs per second using a token
+// bucket that holds up to a hundredth of a second's worth of tokens (at least
+// one), so short bursts pass unthrottled while the sustained rate stays at the
+// cap. Generate and its variants block until a token is available;
+// GenerateContext gives up when its context is done. Pacing follows wall
+// time, not the clock set with WithClock.
+func WithRateLimit(perSecond int) Option {
+	return func(g *Generator) error {
+		if perSecond < 1 {
+			return fmt.Errorf("rate limit must be at least 1 per second: %d", perSecond)
+		}
+		burst := max(1, float64(perSecond)/100)
+		g.limiter = &tokenBucket{
+			rate:   float64(perSecond),
+			burst:  burst,
+			tokens: burst,
+			last:   time.Now(),
+		}
+		return nil
+	}
+}
+
+// GenerateContext creates and returns a unique ID like GenerateSafe, but
+// waits for a WithRateLimit token and for a paused generator to resume,
+// returning ctx's error if ctx is done first.
+func (g *Generator) GenerateContext(ctx context.Context) (ID, error) {
+	if g.layout.FullWidth {
+		return 0, ErrFullWidth
+	}
+	return g.generateWait(ctx, g.node)
+}
+
+// wait takes a token from the bucket, sleeping until one accrues. If ctx is
+// done first the reservation is returned and ctx's error reported.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	deficit := -b.tokens
+	b.mu.Unlock()
+
+	if deficit <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(time.Duration(deficit / b.rate * float64(time.Second)))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
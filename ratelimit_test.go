@@ -0,0 +1,53 @@
+package crystal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	const perSecond = 200
+	gen := New(WithRateLimit(perSecond))
+
+	window := 250 * time.Millisecond
+	start := time.Now()
+	n := 0
+	for time.Since(start) < window {
+		gen.Generate()
+		n++
+	}
+	elapsed := time.Since(start)
+
+	// Allow the initial burst (two tokens) on top of the paced rate.
+	if limit := int(elapsed.Seconds()*perSecond) + 2; n > limit {
+		t.Fatalf("generated %d IDs in %s, want at most %d", n, elapsed, limit)
+	}
+	if n < 10 {
+		t.Fatalf("generated only %d IDs in %s", n, elapsed)
+	}
+}
+
+func TestWithRateLimitInvalid(t *testing.T) {
+	if _, err := NewGenerator(WithRateLimit(0)); err == nil {
+		t.Fatal("NewGenerator(WithRateLimit(0)) succeeded, want error")
+	}
+}
+
+func TestGenerateContextCanceled(t *testing.T) {
+	gen := New(WithRateLimit(1))
+	if _, err := gen.GenerateContext(context.Background()); err != nil {
+		t.Fatalf("GenerateContext() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := gen.GenerateContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GenerateContext() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("GenerateContext() returned after %s, want prompt cancellation", elapsed)
+	}
+}
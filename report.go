@@ -0,0 +1,58 @@
+package crystal
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Report returns a table of how the layout spends its bits: one row per
+// field from the most significant bit down, with the field's width, the
+// range of values it holds and its capacity. Fields the layout does not use
+// (version, kind, node) are left out, as is the unused sign bit of layouts
+// that are not FullWidth. It is meant for operators, e.g. the
+// CLI's info subcommand, and its exact formatting may change.
+func (l Layout) Report() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FIELD\tBITS\tRANGE\tCAPACITY")
+
+	row := func(name string, bits int, valueRange, capacity string) {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", name, bits, valueRange, capacity)
+	}
+	values := func(bits int) (string, string) {
+		n := uint64(1) << uint(bits)
+		return fmt.Sprintf("0-%d", n-1), fmt.Sprintf("%d", n)
+	}
+
+	if bits := l.versionbits(); bits > 0 {
+		r, c := values(bits)
+		row("version", bits, r, c+" versions")
+	}
+
+	first := l.timeAt(0).UTC().Format(time.RFC3339)
+	last := l.timeAt(int64(l.timeMask())).UTC().Format(time.RFC3339) //nolint:gosec
+	_, c := values(l.timebits())
+	row("time", l.timebits(), first+" to "+last, c+" "+l.Precision.String()+"s")
+
+	if bits := l.kindbits(); bits > 0 {
+		r, c := values(bits)
+		row("kind", bits, r, c+" kinds")
+	}
+	if bits := l.nodebits(); bits > 0 {
+		r, c := values(bits)
+		if wb := l.workerbits(); wb > 0 {
+			c += fmt.Sprintf(" nodes (%d datacenter, %d worker bits)", bits-wb, wb)
+		} else {
+			c += " nodes"
+		}
+		row("node", bits, r, c)
+	}
+	r, c := values(l.stepBits())
+	row("sequence", l.stepBits(), r, c+" per "+l.Precision.String())
+	row("total", l.totalBits(), "", "")
+
+	_ = w.Flush()
+	return b.String()
+}
@@ -0,0 +1,30 @@
+package crystal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLayoutReport(t *testing.T) {
+	l := DefaultLayout()
+	l.Nodebits = 5
+
+	report := l.Report()
+	for _, want := range []string{
+		"time      42",
+		"2020-01-01T00:00:00Z to 2159-05-15T07:35:11Z",
+		"node      5",
+		"32 nodes",
+		"sequence  16",
+		"65536 per millisecond",
+		"total     63",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Report() missing %q:\n%s", want, report)
+		}
+	}
+
+	if plain := DefaultLayout().Report(); strings.Contains(plain, "node") || !strings.Contains(plain, "sequence  21") {
+		t.Errorf("Report() without node bits:\n%s", plain)
+	}
+}
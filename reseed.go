@@ -0,0 +1,43 @@
+package crystal
+
+import (
+	"fmt"
+	"time"
+)
+
+// WithReseedInterval makes the generator draw a fresh counter seed at least
+// every d, even while a burst keeps it inside one millisecond, so observers
+// cannot infer the issue rate from how far the steps of consecutive IDs
+// advance. A background goroutine marks a reseed as due every d; the next ID
+// then moves on to the following millisecond with a new seed, since the new
+// starting step could lie below the current one. Each forced reseed thus
+// forfeits the rest of a millisecond's sequence and may put timestamps
+// slightly ahead of the clock; with intervals of milliseconds or more the
+// capacity lost is small. Call Close to stop the goroutine.
+func WithReseedInterval(d time.Duration) Option {
+	return func(g *Generator) error {
+		if d < time.Millisecond {
+			return fmt.Errorf("reseed interval must be at least 1ms: %s", d)
+		}
+		g.reseedInterval = d
+		return nil
+	}
+}
+
+// startReseeder launches the goroutine that marks reseeds as due.
+func (g *Generator) startReseeder() {
+	g.goBackground(func(stop <-chan struct{}) {
+		ticker := time.NewTicker(g.reseedInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				g.mu.Lock()
+				g.reseedDue = true
+				g.mu.Unlock()
+			}
+		}
+	})
+}
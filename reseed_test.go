@@ -0,0 +1,44 @@
+package crystal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithReseedInterval(t *testing.T) {
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	gen := New(WithClock(newManualClock(at)), WithReseedInterval(time.Millisecond))
+	defer gen.Close()
+	l := gen.Layout()
+
+	// The clock is frozen, so without reseeding every step would follow its
+	// predecessor.
+	prev := gen.Generate()
+	jumps := 0
+	deadline := time.Now().Add(2 * time.Second)
+	for jumps < 3 && time.Now().Before(deadline) {
+		time.Sleep(2 * time.Millisecond)
+		for i := 0; i < 10; i++ {
+			id := gen.Generate()
+			if id <= prev {
+				t.Fatalf("ID %d not above %d", id, prev)
+			}
+			if l.Step(id) != l.Step(prev)+1 {
+				jumps++
+				if l.millis(id) != l.millis(prev)+1 {
+					t.Fatalf("reseed moved from millisecond %d to %d, want the next", l.millis(prev), l.millis(id))
+				}
+			}
+			prev = id
+		}
+	}
+	if jumps < 3 {
+		t.Fatalf("saw %d reseeds, want at least 3", jumps)
+	}
+}
+
+func TestWithReseedIntervalInvalid(t *testing.T) {
+	if _, err := NewGenerator(WithReseedInterval(time.Microsecond)); err == nil {
+		t.Fatal("NewGenerator(WithReseedInterval(1µs)) succeeded, want error")
+	}
+}
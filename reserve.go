@@ -0,0 +1,73 @@
+package crystal
+
+import (
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// reserveRefillInterval controls how often the background refiller samples
+// the wall clock for generators using WithReservedWindow.
+const reserveRefillInterval = time.Millisecond
+
+// reservation tracks the window of future milliseconds a generator may hand
+// out IDs from without consulting the wall clock.
+type reservation struct {
+	// span is the configured window; window is span in timestamp units (set
+	// by start), the number of units IDs may run ahead of the clock.
+	span   time.Duration
+	window int64
+	// now holds the most recent epochMillis reading taken by the refiller.
+	now atomic.Int64
+}
+
+// start takes the first clock reading from g and launches the background
+// refiller, which runs until g is closed.
+func (r *reservation) start(g *Generator) {
+	r.window = int64(r.span / g.layout.tick())
+	r.now.Store(g.epochMillis())
+
+	g.goBackground(func(stop <-chan struct{}) {
+		ticker := time.NewTicker(reserveRefillInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				r.refill(g)
+			}
+		}
+	})
+}
+
+// refill records a fresh clock reading from g. Readings that go backwards are
+// reported as drift and ignored, so the window never shrinks.
+func (r *reservation) refill(g *Generator) {
+	now := g.epochMillis()
+	if last := r.now.Load(); now < last {
+		g.observeDrift(last - now)
+		return
+	}
+	r.now.Store(now)
+}
+
+// horizon returns the last millisecond currently covered by the window.
+func (r *reservation) horizon() int64 {
+	return r.now.Load() + r.window
+}
+
+// next returns the millisecond following last, waiting only if it lies
+// beyond the reserved window. When last itself is already past the window
+// (e.g. GenerateAfter jumped to a future watermark) waiting could take
+// arbitrarily long, so it borrows the following millisecond instead.
+func (r *reservation) next(last int64) int64 {
+	next := last + 1
+	if last > r.horizon() {
+		return next
+	}
+	for next > r.horizon() {
+		runtime.Gosched()
+	}
+	return next
+}
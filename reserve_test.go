@@ -0,0 +1,115 @@
+package crystal
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestReservedWindowInvalid(t *testing.T) {
+	if _, err := NewGenerator(WithReservedWindow(0)); err == nil {
+		t.Fatal("expected error for zero reserved window")
+	}
+}
+
+func TestReservedWindowGenerate(t *testing.T) {
+	origTimebits := Timebits
+	t.Cleanup(func() {
+		Timebits = origTimebits
+	})
+
+	// Use the smallest step space so the test crosses many milliseconds.
+	Timebits = maxTimebits
+	window := 50 * time.Millisecond
+
+	gen, err := NewGenerator(WithReservedWindow(window))
+	if err != nil {
+		t.Fatalf("NewGenerator() failed: %v", err)
+	}
+	defer gen.Close()
+
+	const n = 200_000
+	prev := gen.Generate()
+	for i := 1; i < n; i++ {
+		id := gen.Generate()
+		if id <= prev {
+			t.Fatalf("IDs not in order: %d <= %d", id, prev)
+		}
+		prev = id
+	}
+
+	// Allow for the refiller's sampling interval on top of the window.
+	limit := time.Now().Add(window + 2*reserveRefillInterval)
+	if prev.Time().After(limit) {
+		t.Fatalf("ID time %v ran ahead of the reserved window (limit %v)", prev.Time(), limit)
+	}
+}
+
+func TestReservedWindowClose(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	gen := New(WithReservedWindow(10 * time.Millisecond))
+	if runtime.NumGoroutine() <= before {
+		t.Fatal("expected the reserved window to start a refiller goroutine")
+	}
+	_ = gen.Generate()
+
+	if err := gen.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	// Close waits for the refiller, so the goroutine count is back to normal.
+	if after := runtime.NumGoroutine(); after > before {
+		t.Fatalf("refiller goroutine still running: %d goroutines, want %d", after, before)
+	}
+
+	if _, err := gen.GenerateSafe(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("GenerateSafe() after Close error = %v, want ErrClosed", err)
+	}
+
+	if err := gen.Close(); err != nil {
+		t.Fatalf("second Close() failed: %v", err)
+	}
+}
+
+func BenchmarkGenerateReserved(b *testing.B) {
+	gen := New(WithReservedWindow(100 * time.Millisecond))
+	defer gen.Close()
+
+	var worst time.Duration
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		start := time.Now()
+		_ = gen.Generate()
+		if d := time.Since(start); d > worst {
+			worst = d
+		}
+	}
+	b.ReportMetric(float64(worst.Nanoseconds()), "max-ns")
+}
+
+func TestReservedWindowGenerateAfterFuture(t *testing.T) {
+	gen, err := NewGenerator(WithReservedWindow(50 * time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewGenerator() failed: %v", err)
+	}
+
+	// A watermark an hour ahead whose step leaves no room in its millisecond.
+	l := gen.Layout()
+	future := l.ticksSince(time.Now().Add(time.Hour))
+	min := l.compose(future, gen.node, l.stepMask())
+
+	done := make(chan ID, 1)
+	go func() { done <- gen.GenerateAfter(min) }()
+	select {
+	case id := <-done:
+		if id <= min || l.millis(id) != future+1 {
+			t.Fatalf("GenerateAfter() = %d (millisecond %d), want above %d in millisecond %d", id, l.millis(id), min, future+1)
+		}
+	case <-time.After(5 * time.Second):
+		// Closing would wait for the stuck call, so leak the generator.
+		t.Fatal("GenerateAfter() waited for the reserved window to reach the watermark")
+	}
+	_ = gen.Close()
+}
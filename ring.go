@@ -0,0 +1,79 @@
+package crystal
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// Ring hands out pre-generated IDs without ever blocking. See
+// Generator.RingGenerator.
+type Ring struct {
+	ids    chan ID
+	misses atomic.Uint64
+}
+
+// RingGenerator starts a background goroutine that keeps a buffer of size
+// pre-generated IDs filled, and returns a Ring to take them from. It suits
+// fire-and-forget pipelines with bursty load: TryGet never waits, and
+// Misses tells whether consumers outpace the refiller. The refiller waits
+// out Pause and retries other errors (such as ErrBeforeEpoch) after a short
+// delay; it only stops when the generator is closed. RingGenerator panics if
+// size < 1.
+func (g *Generator) RingGenerator(size int) *Ring {
+	if size < 1 {
+		panic("crystal: ring size must be positive")
+	}
+
+	r := &Ring{ids: make(chan ID, size)}
+	g.goBackground(func(stop <-chan struct{}) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			select {
+			case <-stop:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		for {
+			id, err := g.generateWait(ctx, g.node)
+			if errors.Is(err, ErrClosed) || ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				select {
+				case <-time.After(time.Millisecond):
+					continue
+				case <-stop:
+					return
+				}
+			}
+			select {
+			case r.ids <- id:
+			case <-stop:
+				return
+			}
+		}
+	})
+	return r
+}
+
+// TryGet returns the next buffered ID, or false if the buffer is momentarily
+// empty.
+func (r *Ring) TryGet() (ID, bool) {
+	select {
+	case id := <-r.ids:
+		return id, true
+	default:
+		r.misses.Add(1)
+		return 0, false
+	}
+}
+
+// Misses returns how many TryGet calls found the buffer empty.
+func (r *Ring) Misses() uint64 {
+	return r.misses.Load()
+}
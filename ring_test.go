@@ -0,0 +1,69 @@
+package crystal
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestRingGenerator(t *testing.T) {
+	gen := New()
+	t.Cleanup(func() { _ = gen.Close() })
+	ring := gen.RingGenerator(64)
+
+	const (
+		consumers = 8
+		attempts  = 5000
+	)
+	var (
+		mu   sync.Mutex
+		seen = make(map[ID]bool)
+		got  int
+		wg   sync.WaitGroup
+	)
+	for i := 0; i < consumers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var ids []ID
+			for j := 0; j < attempts; j++ {
+				if id, ok := ring.TryGet(); ok {
+					ids = append(ids, id)
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, id := range ids {
+				if seen[id] {
+					t.Errorf("duplicate ID %d", id)
+				}
+				seen[id] = true
+			}
+			got += len(ids)
+		}()
+	}
+	wg.Wait()
+
+	if got == 0 {
+		t.Fatal("TryGet never returned an ID")
+	}
+	if total := uint64(got) + ring.Misses(); total != consumers*attempts {
+		t.Errorf("hits %d + misses %d != %d attempts", got, ring.Misses(), consumers*attempts)
+	}
+}
+
+func TestRingGeneratorClose(t *testing.T) {
+	gen := New()
+	ring := gen.RingGenerator(4)
+	if err := gen.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	// The buffer drains and then stays empty.
+	for i := 0; i < 5; i++ {
+		ring.TryGet()
+	}
+	if _, ok := ring.TryGet(); ok {
+		t.Fatal("TryGet() after Close and drain should report empty")
+	}
+}
@@ -0,0 +1,37 @@
+package crystal
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// WithClusterSecret mixes secret, shared by every generator in a cluster,
+// into the counter seeds alongside any WithTenantSalt salt, so the starting
+// step of each millisecond cannot be reconstructed by someone who knows the
+// host seed but not the secret.
+//
+// This is weak obfuscation, not encryption: timestamps, nodes and the
+// step-by-step increments within a millisecond stay in the clear, so IDs
+// still reveal when and roughly how fast they were issued. Where IDs must be
+// unguessable, encrypt them with a keyed 64-bit block cipher at the API
+// boundary instead.
+func WithClusterSecret(secret []byte) Option {
+	return func(g *Generator) error {
+		g.clusterSecret = append([]byte(nil), secret...)
+		return nil
+	}
+}
+
+// clusterSalt derives the salt fed to initCounter from the tenant salt and
+// the cluster secret. The secret's length is hashed in so that the boundary
+// between secret and salt is unambiguous.
+func clusterSalt(salt, secret []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte("crystal cluster secret"))
+	var n [8]byte
+	binary.BigEndian.PutUint64(n[:], uint64(len(secret)))
+	h.Write(n[:])
+	h.Write(secret)
+	h.Write(salt)
+	return h.Sum(nil)
+}
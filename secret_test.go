@@ -0,0 +1,36 @@
+package crystal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithClusterSecret(t *testing.T) {
+	var seed [32]byte
+	copy(seed[:], "cluster secret test seed")
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	first := func(opts ...Option) uint64 {
+		gen := NewWithSeed(seed, append(opts, WithClock(newManualClock(at)))...)
+		return gen.Layout().Step(gen.Generate())
+	}
+
+	plain, again := first(), first()
+	if plain != again {
+		t.Fatalf("same seed and clock gave steps %d and %d", plain, again)
+	}
+
+	a := first(WithClusterSecret([]byte("alpha")))
+	b := first(WithClusterSecret([]byte("bravo")))
+	if a == b || a == plain || b == plain {
+		t.Fatalf("steps plain=%d alpha=%d bravo=%d, want all different", plain, a, b)
+	}
+	if a2 := first(WithClusterSecret([]byte("alpha"))); a2 != a {
+		t.Fatalf("same secret gave steps %d and %d", a, a2)
+	}
+
+	salted := first(WithTenantSalt([]byte("tenant")), WithClusterSecret([]byte("alpha")))
+	if salted == a {
+		t.Fatal("tenant salt ignored alongside the cluster secret")
+	}
+}
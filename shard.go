@@ -0,0 +1,18 @@
+package crystal
+
+// Shard maps the ID to one of k shards, returning a value in [0, k). The
+// mapping is deterministic, so the same ID always routes to the same shard.
+//
+// A plain modulo would mostly use the low sequence bits, which start at a
+// random value but then increase in lockstep, and would be skewed for k that
+// share factors with the sequence space. Shard instead mixes all 63 bits
+// first, so consecutive IDs, IDs from the same millisecond and IDs from
+// different nodes spread evenly across shards. Changing k remaps most IDs;
+// use a consistent hashing scheme on top if shards are added over time.
+// Shard panics if k < 1.
+func (id ID) Shard(k int) int {
+	if k < 1 {
+		panic("crystal: shard count must be positive")
+	}
+	return int(friendlyMix(id.Uint64()) % uint64(k)) //nolint:gosec
+}
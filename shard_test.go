@@ -0,0 +1,45 @@
+package crystal
+
+import "testing"
+
+func TestShard(t *testing.T) {
+	const (
+		k = 16
+		n = 64_000
+	)
+
+	gen := New()
+	counts := make([]int, k)
+	for i := 0; i < n; i++ {
+		id := gen.Generate()
+		s := id.Shard(k)
+		if s < 0 || s >= k {
+			t.Fatalf("Shard(%d) = %d out of range", k, s)
+		}
+		if again := id.Shard(k); again != s {
+			t.Fatalf("Shard(%d) not stable: %d then %d", k, s, again)
+		}
+		counts[s]++
+	}
+
+	// Consecutive IDs should spread evenly; allow 10% deviation per shard.
+	want := n / k
+	for s, c := range counts {
+		if c < want*9/10 || c > want*11/10 {
+			t.Errorf("shard %d got %d IDs, want about %d", s, c, want)
+		}
+	}
+
+	if got := ID(12345).Shard(1); got != 0 {
+		t.Errorf("Shard(1) = %d, want 0", got)
+	}
+}
+
+func TestShardPanicsOnInvalidCount(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Shard(0) should panic")
+		}
+	}()
+	ID(1).Shard(0)
+}
@@ -0,0 +1,99 @@
+package crystal
+
+import (
+	"encoding/base32"
+	"time"
+)
+
+// ShortID is a compact 48-bit identifier for in-process use: a 32-bit count
+// of seconds since the epoch followed by a 16-bit sequence. It trades range
+// and capacity for size: timestamps only have second precision and last
+// about 136 years from the epoch, and a generator can issue at most 65,536
+// ShortIDs per second (it waits for the next second once the sequence is
+// exhausted). ShortIDs are unique only among those issued by one generator
+// and should not be persisted or shared across processes.
+type ShortID uint64
+
+const (
+	shortTimeBits = 32
+	shortStepBits = 16
+	shortStepMask = uint64(1)<<shortStepBits - 1
+)
+
+// GenerateShort creates a ShortID unique among those issued by g. Like
+// Generate it blocks while the generator is paused and panics with ErrClosed
+// once it is closed. Waiting for the next second after the sequence is
+// exhausted happens without holding the generator's lock, so Generate and
+// its variants are never held up by it.
+func (g *Generator) GenerateShort() ShortID {
+	for {
+		g.lockUnpaused()
+		if g.closed {
+			g.mu.Unlock()
+			panic(ErrClosed)
+		}
+
+		now := g.epochSeconds()
+		if now < g.shortSecond {
+			now = g.shortSecond
+		}
+
+		switch {
+		case now > g.shortSecond:
+			g.shortStep = initCounter(g.seed, g.salt, g.entropy, shortStepMask>>1, &g.randFailures)
+		case g.shortStep < shortStepMask:
+			g.shortStep++
+		default:
+			// The second's sequence is exhausted: wait for the next one
+			// with the lock released and try again.
+			g.mu.Unlock()
+			time.Sleep(time.Millisecond)
+			continue
+		}
+
+		g.shortSecond = now
+		id := ShortID((uint64(now)&(uint64(1)<<shortTimeBits-1))<<shortStepBits | g.shortStep) //nolint:gosec
+		g.mu.Unlock()
+		return id
+	}
+}
+
+// epochSeconds returns whole seconds since the generator's epoch, whatever
+// the layout's precision.
+func (g *Generator) epochSeconds() int64 {
+	return int64(g.layout.timeAt(g.epochMillis()).Sub(g.Epoch()) / time.Second)
+}
+
+// Time returns the timestamp embedded in the ShortID, with second precision,
+// measured from the package-level Epoch.
+func (id ShortID) Time() time.Time {
+	return time.UnixMilli(int64(uint64(id)>>shortStepBits)*1000 + Epoch) //nolint:gosec
+}
+
+// String returns the base32 encoded string representation.
+func (id ShortID) String() string {
+	return id.Base32()
+}
+
+// Base32 returns the 10 character base32 encoded string representation.
+func (id ShortID) Base32() string {
+	b := putUintBytes(6, uint64(id))
+	return base32Encoding.EncodeToString(b)
+}
+
+// ParseShortID parses a base32 encoded string into a ShortID.
+func ParseShortID(s string) (ShortID, error) {
+	b, err := base32Encoding.DecodeString(s)
+	if err != nil {
+		return 0, err
+	}
+	if len(b) != 6 {
+		return 0, base32.CorruptInputError(len(b))
+	}
+
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return ShortID(v), nil
+}
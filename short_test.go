@@ -0,0 +1,144 @@
+package crystal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGenerateShort(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newManualClock(start)
+	gen := New(WithClock(clock))
+
+	// Half the sequence space is always available within a second.
+	const n = 1 << 15
+	seen := make(map[ShortID]bool, n)
+	var prev ShortID
+	for i := 0; i < n; i++ {
+		id := gen.GenerateShort()
+		if seen[id] {
+			t.Fatalf("duplicate ShortID %d", id)
+		}
+		seen[id] = true
+
+		if i > 0 && id <= prev {
+			t.Fatalf("ShortIDs not in order: %d <= %d", id, prev)
+		}
+		prev = id
+
+		if id >= 1<<48 {
+			t.Fatalf("ShortID %d exceeds 48 bits", id)
+		}
+		if !id.Time().Equal(start) {
+			t.Fatalf("ShortID time = %v, want %v", id.Time(), start)
+		}
+	}
+
+	clock.Add(1500 * time.Millisecond)
+	next := gen.GenerateShort()
+	if want := start.Add(time.Second); !next.Time().Equal(want) {
+		t.Fatalf("ShortID time = %v, want %v", next.Time(), want)
+	}
+}
+
+func TestGenerateShortExhaustedUnlocked(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newManualClock(start)
+	gen := New(WithClock(clock))
+
+	// One more than the full sequence space always exhausts the second.
+	const n = 1<<shortStepBits + 1
+	done := make(chan ShortID)
+	go func() {
+		var id ShortID
+		for i := 0; i < n; i++ {
+			id = gen.GenerateShort()
+		}
+		done <- id
+	}()
+
+	for {
+		gen.mu.Lock()
+		exhausted := gen.shortStep == shortStepMask
+		gen.mu.Unlock()
+		if exhausted {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	generated := make(chan ID)
+	go func() { generated <- gen.Generate() }()
+	select {
+	case <-generated:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Generate blocked while GenerateShort waits for the next second")
+	}
+
+	clock.Add(time.Second)
+	select {
+	case id := <-done:
+		if want := start.Add(time.Second); !id.Time().Equal(want) {
+			t.Fatalf("ShortID time = %v, want %v", id.Time(), want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("GenerateShort did not resume after the clock advanced")
+	}
+}
+
+func TestGenerateShortClosed(t *testing.T) {
+	gen := New()
+	if err := gen.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	defer func() {
+		if r := recover(); r != ErrClosed {
+			t.Fatalf("recover() = %v, want %v", r, ErrClosed)
+		}
+	}()
+	gen.GenerateShort()
+}
+
+func TestGenerateShortPrecision(t *testing.T) {
+	origEpoch := Epoch
+	t.Cleanup(func() { Epoch = origEpoch })
+	// 48 microsecond bits last about 8.9 years, so count from a recent epoch.
+	Epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+
+	at := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := DefaultLayout()
+	l.Timebits = maxTimebits
+	for name, gen := range map[string]*Generator{
+		"microsecond": New(WithClock(newManualClock(at)), WithLayout(l), WithPrecision(Microsecond)),
+		"second":      New(WithClock(newManualClock(at)), WithSecondResolution()),
+	} {
+		if got := gen.GenerateShort().Time(); !got.Equal(at) {
+			t.Errorf("%s: ShortID time = %v, want %v", name, got.UTC(), at)
+		}
+	}
+}
+
+func TestShortIDBase32(t *testing.T) {
+	gen := New()
+
+	id := gen.GenerateShort()
+	s := id.String()
+	if len(s) != 10 {
+		t.Fatalf("Base32() length = %d, want 10", len(s))
+	}
+
+	parsed, err := ParseShortID(s)
+	if err != nil {
+		t.Fatalf("ParseShortID() failed: %v", err)
+	}
+	if parsed != id {
+		t.Fatalf("ParseShortID() = %d, want %d", parsed, id)
+	}
+
+	if _, err := ParseShortID(gen.Generate().Base32()); err == nil {
+		t.Error("ParseShortID() should reject a full-length ID")
+	}
+	if _, err := ParseShortID("invalid!@#"); err == nil {
+		t.Error("ParseShortID() should fail for invalid characters")
+	}
+}
@@ -0,0 +1,63 @@
+package crystal
+
+import "fmt"
+
+// Classic Twitter Snowflake layout: 41 bits of milliseconds since the Twitter
+// epoch, 10 bits of machine ID and 12 bits of sequence.
+const (
+	snowflakeEpoch       = int64(1288834974657) // 2010-11-04T01:42:54.657Z
+	snowflakeTimeBits    = 41
+	snowflakeMachineBits = 10
+	snowflakeSeqBits     = 12
+)
+
+// ToSnowflake translates the ID into a classic Twitter Snowflake carrying the
+// same absolute timestamp, the given machine ID (truncated to 10 bits) and
+// the low 12 bits of the ID's sequence. Snowflakes hold far fewer sequence
+// bits (4,096 values per millisecond and machine versus crystal's 2,097,152),
+// so distinct IDs from one millisecond can map to the same Snowflake, and
+// the epoch of 2010 leaves a 41-bit timestamp range ending in 2080.
+func (id ID) ToSnowflake(machineID uint16) int64 {
+	l := layoutFor(id)
+	millis := l.millis(id) + l.Epoch - snowflakeEpoch
+	machine := uint64(machineID) & (1<<snowflakeMachineBits - 1)
+	seq := l.Step(id) & (1<<snowflakeSeqBits - 1)
+
+	//nolint:gosec
+	return int64((uint64(millis)&(1<<snowflakeTimeBits-1))<<(snowflakeMachineBits+snowflakeSeqBits) |
+		machine<<snowflakeSeqBits | seq)
+}
+
+// FromSnowflake translates a classic Twitter Snowflake into an ID under the
+// package-level layout, preserving its absolute timestamp. When the layout
+// has at least 10 node bits the machine ID becomes the node and the sequence
+// the step (which then needs 12 bits); otherwise machine and sequence
+// together (22 bits) become the step. It returns an error wrapping
+// ErrOutOfRange if the Snowflake is negative, predates the epoch or does not
+// fit the layout.
+func FromSnowflake(i int64) (ID, error) {
+	if i < 0 {
+		return 0, fmt.Errorf("%w: negative snowflake %d", ErrOutOfRange, i)
+	}
+
+	u := uint64(i)
+	seq := u & (1<<snowflakeSeqBits - 1)
+	machine := (u >> snowflakeSeqBits) & (1<<snowflakeMachineBits - 1)
+	abs := int64(u>>(snowflakeMachineBits+snowflakeSeqBits)) + snowflakeEpoch //nolint:gosec
+
+	l := DefaultLayout()
+	millis := abs - l.Epoch
+	if millis < 0 || millis != l.clampMillis(millis) {
+		return 0, fmt.Errorf("%w: snowflake time %d does not fit the layout", ErrOutOfRange, abs)
+	}
+
+	node, step := machine, seq
+	if l.nodebits() < snowflakeMachineBits {
+		node, step = 0, machine<<snowflakeSeqBits|seq
+	}
+	if step > l.stepMask() {
+		return 0, fmt.Errorf("%w: snowflake machine %d and sequence %d do not fit in %d step bits",
+			ErrOutOfRange, machine, seq, l.stepBits())
+	}
+	return l.compose(millis, node, step), nil
+}
@@ -0,0 +1,70 @@
+package crystal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSnowflakeRoundTrip(t *testing.T) {
+	ts := time.Date(2024, 6, 1, 12, 0, 0, 123_000_000, time.UTC)
+	gen := New(WithClock(newManualClock(ts)))
+	id := gen.Generate()
+
+	sf := id.ToSnowflake(300)
+	if got := time.UnixMilli(sf>>22 + snowflakeEpoch); !got.Equal(ts) {
+		t.Fatalf("snowflake time = %v, want %v", got, ts)
+	}
+	if machine := (sf >> 12) & 0x3ff; machine != 300 {
+		t.Fatalf("snowflake machine = %d, want 300", machine)
+	}
+
+	back, err := FromSnowflake(sf)
+	if err != nil {
+		t.Fatalf("FromSnowflake() failed: %v", err)
+	}
+	if !back.Time().Equal(ts) {
+		t.Fatalf("FromSnowflake() time = %v, want %v", back.Time(), ts)
+	}
+	if again := back.ToSnowflake(300); again != sf {
+		t.Fatalf("snowflake round trip = %d, want %d", again, sf)
+	}
+}
+
+func TestFromSnowflakeNodeBits(t *testing.T) {
+	origTimebits, origNodebits := Timebits, Nodebits
+	t.Cleanup(func() {
+		Timebits, Nodebits = origTimebits, origNodebits
+	})
+	Timebits, Nodebits = 41, 10 // 12 step bits
+
+	ts := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	sf := (ts.UnixMilli()-snowflakeEpoch)<<22 | 1023<<12 | 4095
+
+	id, err := FromSnowflake(sf)
+	if err != nil {
+		t.Fatalf("FromSnowflake() failed: %v", err)
+	}
+	if id.Node() != 1023 || DefaultLayout().Step(id) != 4095 {
+		t.Fatalf("node/step = %d/%d, want 1023/4095", id.Node(), DefaultLayout().Step(id))
+	}
+	if id.ToSnowflake(1023) != sf {
+		t.Fatalf("ToSnowflake() = %d, want %d", id.ToSnowflake(1023), sf)
+	}
+}
+
+func TestFromSnowflakeOutOfRange(t *testing.T) {
+	// 2015: before crystal's 2020 epoch.
+	early := (time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli() - snowflakeEpoch) << 22
+	for _, sf := range []int64{-1, early} {
+		if _, err := FromSnowflake(sf); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("FromSnowflake(%d) error = %v, want ErrOutOfRange", sf, err)
+		}
+	}
+
+	// Machine 1023 with 21 step bits does not fit (22 bits needed).
+	late := (time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()-snowflakeEpoch)<<22 | 1023<<12
+	if _, err := FromSnowflake(late); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("FromSnowflake(machine 1023) error = %v, want ErrOutOfRange", err)
+	}
+}
@@ -0,0 +1,55 @@
+package crystal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sortKeyDigits is the width of the millisecond prefix of a sort key, enough
+// for any 48-bit timestamp from a post-1970 epoch.
+const sortKeyDigits = 15
+
+// SortKey returns id as a string key of the form
+// <zero-padded-unix-millis>-<base32>, e.g. for object-store keys. The fixed
+// width prefix makes keys sort chronologically as strings, while the embedded
+// ID keeps them unique. The timestamp is decoded under layout l.
+func (id ID) SortKey(l Layout) string {
+	return fmt.Sprintf("%0*d-%s", sortKeyDigits, l.Time(id).UnixMilli(), id.Base32())
+}
+
+// ParseSortKey parses a key produced by SortKey and returns the embedded ID.
+// The ID's timestamp, decoded with the package-level layout (or the layout
+// registered for its version tag), must match the prefix; a mismatch returns
+// an error wrapping ErrInvalidID. Use ParseSortKeyLayout for keys made under
+// any other layout.
+func ParseSortKey(s string) (ID, error) {
+	return parseSortKey(s, layoutFor)
+}
+
+// ParseSortKeyLayout is like ParseSortKey but decodes the ID's timestamp
+// under layout l, the one the key was made with.
+func ParseSortKeyLayout(s string, l Layout) (ID, error) {
+	return parseSortKey(s, func(ID) Layout { return l })
+}
+
+// parseSortKey parses s, checking the prefix against the ID's time under the
+// layout returned by layout.
+func parseSortKey(s string, layout func(ID) Layout) (ID, error) {
+	prefix, encoded, ok := strings.Cut(s, "-")
+	if !ok || len(prefix) != sortKeyDigits {
+		return 0, fmt.Errorf("%w: malformed sort key %q", ErrInvalidID, s)
+	}
+	millis, err := strconv.ParseInt(prefix, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: malformed sort key %q", ErrInvalidID, s)
+	}
+	id, err := ParseBase32(encoded)
+	if err != nil {
+		return 0, err
+	}
+	if got := layout(id).Time(id).UnixMilli(); got != millis {
+		return 0, fmt.Errorf("%w: sort key prefix %d does not match ID time %d", ErrInvalidID, millis, got)
+	}
+	return id, nil
+}
@@ -0,0 +1,74 @@
+package crystal
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSortKeyRoundTrip(t *testing.T) {
+	ts := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	gen := New(WithClock(newManualClock(ts)))
+	l := gen.Layout()
+
+	prev := ""
+	for i := 0; i < 10; i++ {
+		id := gen.Generate()
+		key := id.SortKey(l)
+		if !strings.HasPrefix(key, "001717243200000-") {
+			t.Fatalf("SortKey = %q, want prefix 001717243200000-", key)
+		}
+		if key <= prev {
+			t.Fatalf("key %q does not sort after %q", key, prev)
+		}
+		prev = key
+
+		got, err := ParseSortKey(key)
+		if err != nil {
+			t.Fatalf("ParseSortKey(%q): %v", key, err)
+		}
+		if got != id {
+			t.Fatalf("ParseSortKey(%q) = %d, want %d", key, got, id)
+		}
+	}
+}
+
+func TestParseSortKeyLayout(t *testing.T) {
+	ts := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	l := DefaultLayout()
+	l.Epoch = time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	gen := New(WithClock(newManualClock(ts)), WithLayout(l))
+
+	id := gen.Generate()
+	key := id.SortKey(l)
+	if !strings.HasPrefix(key, "001717243200000-") {
+		t.Fatalf("SortKey = %q, want prefix 001717243200000-", key)
+	}
+	got, err := ParseSortKeyLayout(key, l)
+	if err != nil || got != id {
+		t.Fatalf("ParseSortKeyLayout(%q) = %d, %v, want %d", key, got, err, id)
+	}
+	if _, err := ParseSortKey(key); !errors.Is(err, ErrInvalidID) {
+		t.Fatalf("ParseSortKey(%q) error = %v, want ErrInvalidID", key, err)
+	}
+}
+
+func TestParseSortKeyMismatch(t *testing.T) {
+	ts := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	id := New(WithClock(newManualClock(ts))).Generate()
+
+	key := "001717243200001-" + id.Base32()
+	if _, err := ParseSortKey(key); !errors.Is(err, ErrInvalidID) {
+		t.Fatalf("ParseSortKey(%q) error = %v, want ErrInvalidID", key, err)
+	}
+
+	for _, bad := range []string{"", id.Base32(), "1717243200000-" + id.Base32(), "00171724320000x-" + id.Base32()} {
+		if _, err := ParseSortKey(bad); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("ParseSortKey(%q) error = %v, want ErrInvalidID", bad, err)
+		}
+	}
+	if _, err := ParseSortKey("001717243200000-!!"); err == nil {
+		t.Error("ParseSortKey accepted an invalid base32 ID")
+	}
+}
@@ -0,0 +1,97 @@
+package crystal
+
+import (
+	"fmt"
+	"time"
+)
+
+// GenerateRange returns perMillis unique IDs for every millisecond (or other
+// timestamp unit of the layout's Precision) in [start, end), in ascending
+// order. It is intended for building realistic historical datasets (e.g.
+// load-testing a time-series store) and does not affect the generator's live
+// sequence.
+//
+// Each millisecond's sequence starts from a fresh seeded counter, so perMillis
+// may use at most half of the step space (2^(stepBits-1) IDs).
+func (g *Generator) GenerateRange(start, end time.Time, perMillis int) ([]ID, error) {
+	l := g.layout
+	if l.FullWidth {
+		return nil, ErrFullWidth
+	}
+	mask := l.stepMask()
+
+	if perMillis < 1 || uint64(perMillis) > mask-l.stepSeedMask() {
+		return nil, fmt.Errorf("perMillis out of range: %d", perMillis)
+	}
+
+	from := l.ticksSince(start)
+	to := l.ticksSince(end)
+	if from < 0 {
+		return nil, fmt.Errorf("range starts before epoch: %s", start)
+	}
+	if to <= from {
+		return []ID{}, nil
+	}
+	if to-1 > int64(1)<<uint(l.timebits())-1 {
+		return nil, fmt.Errorf("range ends beyond timestamp capacity: %s", end)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ids := make([]ID, 0, (to-from)*int64(perMillis))
+	for millis := from; millis < to; millis++ {
+		step := g.newCounter()
+		for i := 0; i < perMillis; i++ {
+			ids = append(ids, l.withKind(l.compose(millis, g.node, step+uint64(i)), g.kind))
+		}
+	}
+	return ids, nil
+}
+
+// GenerateSpread returns n unique IDs in ascending order whose timestamps are
+// evenly spaced across [now-window, now] by the generator's clock, e.g. to
+// seed cursor-pagination tests. The window is clamped to start no earlier
+// than the epoch. Like GenerateRange it draws a fresh seeded counter for
+// each millisecond and leaves the live sequence alone, so the IDs are meant
+// for test datasets and may collide with IDs issued live in the same
+// milliseconds. Like GenerateRange, no millisecond may receive more than half
+// the step space; a window too narrow for n IDs panics with an error
+// wrapping ErrOutOfRange. It panics with ErrFullWidth for a WithFullWidth
+// generator.
+func (g *Generator) GenerateSpread(n int, window time.Duration) []ID {
+	l := g.layout
+	if l.FullWidth {
+		panic(ErrFullWidth)
+	}
+	if n <= 0 {
+		return []ID{}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	to := g.epochMillis()
+	from := max(0, to-int64(window/l.tick()))
+
+	ids := make([]ID, n)
+	limit := l.stepMask() - l.stepSeedMask()
+	last, step, count := int64(-1), uint64(0), uint64(0)
+	for i := range ids {
+		millis := to
+		if n > 1 {
+			millis = from + (to-from)*int64(i)/int64(n-1)
+		}
+		if millis != last {
+			last, step, count = millis, g.newCounter(), 1
+		} else {
+			step++
+			count++
+		}
+		if count > limit {
+			panic(fmt.Errorf("%w: %d IDs do not fit a %s window at %d per millisecond", ErrOutOfRange, n, window, limit))
+		}
+		ids[i] = l.withKind(l.compose(millis, g.node, step), g.kind)
+	}
+	return ids
+}
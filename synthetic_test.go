@@ -0,0 +1,142 @@
+package crystal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGenerateRange(t *testing.T) {
+	gen := New()
+
+	end := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	start := end.Add(-25 * time.Millisecond)
+	const perMillis = 40
+
+	ids, err := gen.GenerateRange(start, end, perMillis)
+	if err != nil {
+		t.Fatalf("GenerateRange() failed: %v", err)
+	}
+
+	if len(ids) != 25*perMillis {
+		t.Fatalf("expected %d IDs, got %d", 25*perMillis, len(ids))
+	}
+
+	seen := make(map[ID]bool, len(ids))
+	for i, id := range ids {
+		if seen[id] {
+			t.Fatalf("duplicate ID generated: %d", id)
+		}
+		seen[id] = true
+
+		if i > 0 && id <= ids[i-1] {
+			t.Fatalf("IDs not in order: %d <= %d", id, ids[i-1])
+		}
+
+		if ts := id.Time(); ts.Before(start) || !ts.Before(end) {
+			t.Fatalf("ID time %v outside [%v, %v)", ts, start, end)
+		}
+	}
+}
+
+func TestGenerateRangePrecision(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := DefaultLayout()
+	l.Epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
+	l.Timebits = maxTimebits
+	gen := New(WithClock(newManualClock(now)), WithLayout(l), WithPrecision(Microsecond))
+	gl := gen.Layout()
+
+	ids, err := gen.GenerateRange(now, now.Add(time.Millisecond), 1)
+	if err != nil {
+		t.Fatalf("GenerateRange() failed: %v", err)
+	}
+	if len(ids) != 1000 {
+		t.Fatalf("GenerateRange() returned %d IDs, want one per microsecond", len(ids))
+	}
+	if first, last := gl.Time(ids[0]), gl.Time(ids[len(ids)-1]); !first.Equal(now) || !last.Equal(now.Add(999*time.Microsecond)) {
+		t.Fatalf("range covers [%v, %v], want [%v, %v]", first.UTC(), last.UTC(), now, now.Add(999*time.Microsecond))
+	}
+}
+
+func TestGenerateRangeInvalid(t *testing.T) {
+	gen := New()
+	end := time.Now()
+	start := end.Add(-time.Millisecond)
+
+	if _, err := gen.GenerateRange(start, end, 0); err == nil {
+		t.Error("expected error for zero perMillis")
+	}
+
+	if _, err := gen.GenerateRange(start, end, int(currentStepMask())); err == nil {
+		t.Error("expected error for perMillis exceeding the step space")
+	}
+
+	beforeEpoch := time.UnixMilli(Epoch).Add(-time.Second)
+	if _, err := gen.GenerateRange(beforeEpoch, end, 1); err == nil {
+		t.Error("expected error for range starting before epoch")
+	}
+
+	ids, err := gen.GenerateRange(end, start, 1)
+	if err != nil || len(ids) != 0 {
+		t.Errorf("expected empty result for reversed range, got %d IDs, err %v", len(ids), err)
+	}
+}
+
+func TestGenerateSpread(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	gen := New(WithClock(newManualClock(now)))
+
+	const n = 1000
+	ids := gen.GenerateSpread(n, time.Hour)
+	if len(ids) != n {
+		t.Fatalf("GenerateSpread() returned %d IDs, want %d", len(ids), n)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("ID %d at index %d not above %d", ids[i], i, ids[i-1])
+		}
+	}
+
+	first, last := ids[0].Time(), ids[n-1].Time()
+	if !first.Equal(now.Add(-time.Hour)) || !last.Equal(now) {
+		t.Fatalf("spread covers [%v, %v], want [%v, %v]", first, last, now.Add(-time.Hour), now)
+	}
+
+	// Many IDs per millisecond when the window is tiny.
+	dense := gen.GenerateSpread(50, 2*time.Millisecond)
+	for i := 1; i < len(dense); i++ {
+		if dense[i] <= dense[i-1] {
+			t.Fatalf("dense ID %d at index %d not above %d", dense[i], i, dense[i-1])
+		}
+	}
+	if got := len(gen.GenerateSpread(0, time.Hour)); got != 0 {
+		t.Fatalf("GenerateSpread(0) returned %d IDs", got)
+	}
+}
+
+func TestGenerateSpreadCapacity(t *testing.T) {
+	origTimebits, origNodebits := Timebits, Nodebits
+	t.Cleanup(func() {
+		Timebits, Nodebits = origTimebits, origNodebits
+	})
+	Timebits, Nodebits = maxTimebits, 9 // 6 step bits, 32 IDs per millisecond
+
+	gen := New(WithClock(newManualClock(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))))
+	ids := gen.GenerateSpread(32, 0)
+	seen := make(map[ID]bool)
+	for i, id := range ids {
+		if seen[id] || (i > 0 && id <= ids[i-1]) {
+			t.Fatalf("ID %d at index %d is a duplicate or out of order", id, i)
+		}
+		seen[id] = true
+	}
+
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, ErrOutOfRange) {
+			t.Fatalf("GenerateSpread(33, 0) panicked with %v, want ErrOutOfRange", err)
+		}
+	}()
+	gen.GenerateSpread(33, 0)
+}
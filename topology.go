@@ -0,0 +1,62 @@
+package crystal
+
+import "fmt"
+
+// topology collects the datacenter and worker fields requested through
+// WithDatacenter and WithWorker until the generator is fully configured.
+type topology struct {
+	datacenter, datacenterBits uint
+	worker, workerBits         uint
+}
+
+// WithDatacenter stamps every ID with the datacenter dc in a field of dcBits
+// bits. Together with WithWorker it replaces the layout's node field: the
+// datacenter occupies its high bits and the worker its low bits, both carved
+// out of the sequence space. Decode the fields with the generator's Layout,
+// or set the package-level Nodebits and Workerbits to match before using
+// ID.Datacenter and ID.Worker.
+func WithDatacenter(dc uint8, dcBits uint) Option {
+	return func(g *Generator) error {
+		if g.topology == nil {
+			g.topology = &topology{}
+		}
+		g.topology.datacenter = uint(dc)
+		g.topology.datacenterBits = dcBits
+		return nil
+	}
+}
+
+// WithWorker stamps every ID with the worker w in a field of workerBits bits,
+// below the datacenter field. See WithDatacenter.
+func WithWorker(w uint8, workerBits uint) Option {
+	return func(g *Generator) error {
+		if g.topology == nil {
+			g.topology = &topology{}
+		}
+		g.topology.worker = uint(w)
+		g.topology.workerBits = workerBits
+		return nil
+	}
+}
+
+// apply validates the topology against the generator's layout and rewrites
+// the layout's node field and the generator's node to carry it.
+func (t *topology) apply(g *Generator) error {
+	available := uint(g.layout.fieldBits() - g.layout.timebits() - g.layout.kindbits())
+	if t.datacenterBits > 8 || t.workerBits > 8 ||
+		t.datacenterBits+t.workerBits+1 > available {
+		return fmt.Errorf("datacenter (%d bits) and worker (%d bits) do not fit in %d non-time bits",
+			t.datacenterBits, t.workerBits, available)
+	}
+	if t.datacenter >= 1<<t.datacenterBits {
+		return fmt.Errorf("datacenter %d does not fit in %d bits", t.datacenter, t.datacenterBits)
+	}
+	if t.worker >= 1<<t.workerBits {
+		return fmt.Errorf("worker %d does not fit in %d bits", t.worker, t.workerBits)
+	}
+
+	g.layout.Nodebits = int(t.datacenterBits + t.workerBits)
+	g.layout.Workerbits = int(t.workerBits)
+	g.node = uint64(t.datacenter<<t.workerBits | t.worker)
+	return nil
+}
@@ -0,0 +1,116 @@
+package crystal
+
+import "testing"
+
+func TestDatacenterWorker(t *testing.T) {
+	gen, err := NewGenerator(WithDatacenter(5, 3), WithWorker(9, 4))
+	if err != nil {
+		t.Fatalf("NewGenerator() failed: %v", err)
+	}
+
+	l := gen.Layout()
+	if l.Nodebits != 7 || l.Workerbits != 4 {
+		t.Fatalf("unexpected layout node bits %d, worker bits %d", l.Nodebits, l.Workerbits)
+	}
+
+	id := gen.Generate()
+	if l.Datacenter(id) != 5 {
+		t.Errorf("Datacenter() = %d, want 5", l.Datacenter(id))
+	}
+	if l.Worker(id) != 9 {
+		t.Errorf("Worker() = %d, want 9", l.Worker(id))
+	}
+	if l.Node(id) != 5<<4|9 {
+		t.Errorf("Node() = %d, want %d", l.Node(id), 5<<4|9)
+	}
+}
+
+func TestDatacenterWorkerPackageLayout(t *testing.T) {
+	origNodebits, origWorkerbits := Nodebits, Workerbits
+	t.Cleanup(func() {
+		Nodebits, Workerbits = origNodebits, origWorkerbits
+	})
+
+	Nodebits, Workerbits = 6, 2
+
+	gen := New(WithWorker(3, 2), WithDatacenter(12, 4))
+	id := gen.Generate()
+
+	if id.Datacenter() != 12 {
+		t.Errorf("ID.Datacenter() = %d, want 12", id.Datacenter())
+	}
+	if id.Worker() != 3 {
+		t.Errorf("ID.Worker() = %d, want 3", id.Worker())
+	}
+}
+
+func TestDatacenterWorkerOverAllocation(t *testing.T) {
+	origTimebits := Timebits
+	t.Cleanup(func() {
+		Timebits = origTimebits
+	})
+
+	// 48 time bits leave 15 bits; 8+7 would consume the last sequence bit.
+	Timebits = 48
+	if _, err := NewGenerator(WithDatacenter(1, 8), WithWorker(1, 7)); err == nil {
+		t.Fatal("expected error when datacenter and worker bits exhaust the sequence")
+	}
+	if _, err := NewGenerator(WithDatacenter(1, 8), WithWorker(1, 6)); err != nil {
+		t.Fatalf("expected 8+6 bits to fit, got %v", err)
+	}
+
+	if _, err := NewGenerator(WithDatacenter(8, 3)); err == nil {
+		t.Fatal("expected error for datacenter exceeding its bits")
+	}
+	if _, err := NewGenerator(WithWorker(4, 2)); err == nil {
+		t.Fatal("expected error for worker exceeding its bits")
+	}
+}
+
+func TestWithKind(t *testing.T) {
+	users := New(WithKind(1, 4), WithNode(0))
+	orders := New(WithKind(9, 4), WithDatacenter(2, 3))
+
+	for i := 0; i < 100; i++ {
+		u, o := users.Generate(), orders.Generate()
+		if k := users.Layout().Kind(u); k != 1 {
+			t.Fatalf("user ID kind = %d, want 1", k)
+		}
+		if k := orders.Layout().Kind(o); k != 9 {
+			t.Fatalf("order ID kind = %d, want 9", k)
+		}
+		if dc := orders.Layout().Datacenter(o); dc != 2 {
+			t.Fatalf("order ID datacenter = %d, want 2", dc)
+		}
+	}
+
+	if _, err := NewGenerator(WithKind(16, 4)); err == nil {
+		t.Fatal("expected error for kind exceeding its bits")
+	}
+	if _, err := NewGenerator(WithKind(1, 9)); err == nil {
+		t.Fatal("expected error for more than 8 kind bits")
+	}
+}
+
+func TestWithKindPackageLayout(t *testing.T) {
+	origTimebits, origKindbits, origNodebits := Timebits, Kindbits, Nodebits
+	t.Cleanup(func() {
+		Timebits, Kindbits, Nodebits = origTimebits, origKindbits, origNodebits
+	})
+
+	Kindbits = 3
+	id := New(WithKind(5, 3)).Generate()
+	if id.Kind() != 5 {
+		t.Errorf("ID.Kind() = %d, want 5", id.Kind())
+	}
+
+	// 48 time bits leave 15 bits; 8 kind and 7 node bits would consume the
+	// last sequence bit.
+	Timebits, Nodebits = 48, 7
+	if _, err := NewGenerator(WithKind(1, 8)); err == nil {
+		t.Fatal("expected error when kind and node bits exhaust the sequence")
+	}
+	if _, err := NewGenerator(WithDatacenter(1, 7), WithKind(1, 8)); err == nil {
+		t.Fatal("expected error when kind and datacenter bits exhaust the sequence")
+	}
+}
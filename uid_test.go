@@ -0,0 +1,82 @@
+package crystal
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestGenerateU(t *testing.T) {
+	// Far enough from the epoch that the timestamp's top bit is set.
+	start := time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+	gen := New(WithClock(newManualClock(start)), WithFullWidth())
+
+	if got, want := gen.Layout().stepMask(), DefaultLayout().stepMask()<<1|1; got != want {
+		t.Fatalf("full-width step mask = %#x, want %#x", got, want)
+	}
+
+	var prev UID
+	for i := 0; i < 1000; i++ {
+		u := gen.GenerateU()
+		if u.Uint64() <= math.MaxInt64 {
+			t.Fatalf("UID %d does not exceed math.MaxInt64", u)
+		}
+		if u <= prev {
+			t.Fatalf("UIDs not in order: %d <= %d", u, prev)
+		}
+		prev = u
+
+		if !u.Time().Equal(start) {
+			t.Fatalf("UID time = %v, want %v", u.Time(), start)
+		}
+	}
+
+	if _, err := gen.GenerateSafe(); !errors.Is(err, ErrFullWidth) {
+		t.Fatalf("GenerateSafe() on full-width generator = %v, want ErrFullWidth", err)
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r != ErrNotFullWidth {
+				t.Fatalf("GenerateU() on 63-bit generator panicked with %v", r)
+			}
+		}()
+		New().GenerateU()
+	}()
+}
+
+func TestUIDEncoding(t *testing.T) {
+	u := UID(math.MaxUint64 - 12345)
+
+	s := u.String()
+	if len(s) != 13 {
+		t.Fatalf("Base32() length = %d, want 13", len(s))
+	}
+	parsed, err := ParseUID(s)
+	if err != nil {
+		t.Fatalf("ParseUID() failed: %v", err)
+	}
+	if parsed != u {
+		t.Fatalf("ParseUID() = %d, want %d", parsed, u)
+	}
+
+	h := u.Hex()
+	if h != "ffffffffffffcfc6" {
+		t.Fatalf("Hex() = %q", h)
+	}
+	parsed, err = ParseUIDHex(h)
+	if err != nil {
+		t.Fatalf("ParseUIDHex() failed: %v", err)
+	}
+	if parsed != u {
+		t.Fatalf("ParseUIDHex() = %d, want %d", parsed, u)
+	}
+
+	if _, err := ParseUID("invalid!@#"); err == nil {
+		t.Error("ParseUID() should fail for invalid characters")
+	}
+	if _, err := ParseUIDHex("abcd"); err == nil {
+		t.Error("ParseUIDHex() should fail for short input")
+	}
+}
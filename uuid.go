@@ -0,0 +1,44 @@
+package crystal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// GenerateUUIDv7 returns a new RFC 9562 version 7 UUID: a 48-bit big-endian
+// Unix millisecond timestamp followed by the version and variant bits, with
+// the remaining 74 bits filled from crypto/rand. UUIDv7 values sort by
+// creation time and are accepted by any UUIDv7 parser. Format the result with
+// FormatUUID.
+func GenerateUUIDv7() [16]byte {
+	var u [16]byte
+	if _, err := rand.Read(u[6:]); err != nil {
+		panic("crystal: crypto/rand failed: " + err.Error())
+	}
+
+	millis := uint64(time.Now().UnixMilli()) //nolint:gosec
+	for i := 5; i >= 0; i-- {
+		u[i] = byte(millis)
+		millis >>= 8
+	}
+
+	u[6] = 0x70 | (u[6] & 0x0f) // version 7
+	u[8] = 0x80 | (u[8] & 0x3f) // variant 10 (RFC 9562)
+	return u
+}
+
+// FormatUUID returns the canonical lowercase 8-4-4-4-12 string form of u.
+func FormatUUID(u [16]byte) string {
+	var b [36]byte
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+	return string(b[:])
+}
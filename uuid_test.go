@@ -0,0 +1,47 @@
+package crystal
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestGenerateUUIDv7(t *testing.T) {
+	before := time.Now().UnixMilli()
+	u := GenerateUUIDv7()
+	after := time.Now().UnixMilli()
+
+	if version := u[6] >> 4; version != 7 {
+		t.Errorf("version nibble = %d, want 7", version)
+	}
+	if variant := u[8] >> 6; variant != 0b10 {
+		t.Errorf("variant bits = %02b, want 10", variant)
+	}
+
+	var millis int64
+	for _, b := range u[:6] {
+		millis = millis<<8 | int64(b)
+	}
+	if millis < before || millis > after {
+		t.Errorf("timestamp %d outside [%d, %d]", millis, before, after)
+	}
+
+	if other := GenerateUUIDv7(); other == u {
+		t.Error("consecutive UUIDs are identical")
+	}
+}
+
+func TestFormatUUID(t *testing.T) {
+	u := [16]byte{
+		0x01, 0x8f, 0x3a, 0x2b, 0x4c, 0x5d, 0x7e, 0x6f,
+		0x80, 0x91, 0xa2, 0xb3, 0xc4, 0xd5, 0xe6, 0xf7,
+	}
+	if got, want := FormatUUID(u), "018f3a2b-4c5d-7e6f-8091-a2b3c4d5e6f7"; got != want {
+		t.Errorf("FormatUUID() = %q, want %q", got, want)
+	}
+
+	canonical := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if s := FormatUUID(GenerateUUIDv7()); !canonical.MatchString(s) {
+		t.Errorf("FormatUUID(GenerateUUIDv7()) = %q is not a canonical UUIDv7", s)
+	}
+}
@@ -0,0 +1,56 @@
+package crystal
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidID is returned when a value cannot be an ID issued by a
+// generator.
+var ErrInvalidID = errors.New("crystal: invalid ID")
+
+// Validate reports whether id could have been issued by a generator sharing
+// g's layout. It rejects zero and negative values as well as IDs whose
+// timestamp lies beyond the latest millisecond g could have issued: the
+// current clock reading, or the last millisecond g itself handed out when it
+// is running ahead of the clock. Errors wrap ErrInvalidID.
+func (g *Generator) Validate(id ID) error {
+	return g.validate(id, g.validLimit())
+}
+
+// ValidateAll partitions ids into those that pass Validate and those that do
+// not, preserving their order. The clock is read once for the whole batch.
+func (g *Generator) ValidateAll(ids []ID) (valid []ID, invalid []ID) {
+	limit := g.validLimit()
+	for _, id := range ids {
+		if g.validate(id, limit) == nil {
+			valid = append(valid, id)
+		} else {
+			invalid = append(invalid, id)
+		}
+	}
+	return valid, invalid
+}
+
+// validLimit returns the latest millisecond since the epoch that a valid ID
+// may carry.
+func (g *Generator) validLimit() int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	limit := g.epochMillis()
+	if g.reserve != nil {
+		limit = max(limit, g.reserve.horizon())
+	}
+	return max(limit, g.lastMillis)
+}
+
+func (g *Generator) validate(id ID, limit int64) error {
+	if id <= 0 {
+		return fmt.Errorf("%w: %d is not positive", ErrInvalidID, id)
+	}
+	if millis := g.layout.millis(id); millis > limit {
+		return fmt.Errorf("%w: %d has a timestamp in the future", ErrInvalidID, id)
+	}
+	return nil
+}
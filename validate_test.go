@@ -0,0 +1,64 @@
+package crystal
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestValidateAll(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newManualClock(start)
+	gen := New(WithClock(clock))
+
+	var ids, want []ID
+	for i := 0; i < 10; i++ {
+		id := gen.Generate()
+		ids = append(ids, id)
+		want = append(want, id)
+	}
+
+	future := ID(int64(time.Hour/time.Millisecond)+start.UnixMilli()-Epoch) << gen.Layout().timeShift()
+	bad := []ID{0, -1, math.MinInt64, future, math.MaxInt64}
+	ids = append(ids[:5], append(append([]ID{}, bad...), ids[5:]...)...)
+
+	valid, invalid := gen.ValidateAll(ids)
+	if len(valid) != len(want) {
+		t.Fatalf("got %d valid IDs, want %d", len(valid), len(want))
+	}
+	for i := range want {
+		if valid[i] != want[i] {
+			t.Fatalf("valid[%d] = %d, want %d", i, valid[i], want[i])
+		}
+	}
+	if len(invalid) != len(bad) {
+		t.Fatalf("got %d invalid IDs, want %d", len(invalid), len(bad))
+	}
+	for i := range bad {
+		if invalid[i] != bad[i] {
+			t.Fatalf("invalid[%d] = %d, want %d", i, invalid[i], bad[i])
+		}
+	}
+
+	if err := gen.Validate(future); !errors.Is(err, ErrInvalidID) {
+		t.Fatalf("Validate(future) = %v, want ErrInvalidID", err)
+	}
+
+	// Once the clock catches up the same ID becomes valid.
+	clock.Add(time.Hour)
+	if err := gen.Validate(future); err != nil {
+		t.Fatalf("Validate(future) after clock advance = %v", err)
+	}
+}
+
+func TestValidateBorrowedMillis(t *testing.T) {
+	clock := newManualClock(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	gen := New(WithClock(clock))
+
+	// IDs issued ahead of the clock by GenerateAfter remain valid.
+	ahead := gen.GenerateAfter(ID(gen.epochMillis()+1000) << gen.Layout().timeShift())
+	if err := gen.Validate(ahead); err != nil {
+		t.Fatalf("Validate(borrowed) = %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package crystal
+
+import (
+	"fmt"
+	"sync"
+)
+
+// layoutRegistry maps version tags to the layouts registered for them.
+//
+//nolint:gochecknoglobals
+var layoutRegistry = struct {
+	mu      sync.RWMutex
+	layouts map[uint8]Layout
+}{layouts: make(map[uint8]Layout)}
+
+// RegisterLayout records l as the layout of IDs tagged with l.Version, so that
+// ID methods without a Layout argument (Time, Node, ...) decode such IDs with
+// l even after the package-level configuration has moved on. Generators
+// created with Versionbits set register their layout automatically; call
+// RegisterLayout directly in processes that only decode.
+//
+// Versioning lets a deployment change Timebits (or any other width) without
+// misdecoding stored IDs: bump LayoutVersion along with the change and each ID
+// carries the version of the layout it was issued under. The tag occupies the
+// top Versionbits bits (at most 2, so 4 versions), which are taken from the
+// sequence space. Since the tag is the most significant field, IDs of a
+// higher version sort above all IDs of lower versions.
+//
+// RegisterLayout fails if l has no version bits, if its version does not fit
+// them, or if a different layout is already registered for the version.
+func RegisterLayout(l Layout) error {
+	if l.versionbits() == 0 {
+		return fmt.Errorf("layout has no version bits")
+	}
+	if uint64(l.Version) > l.versionMask() {
+		return fmt.Errorf("version %d does not fit in %d version bits", l.Version, l.versionbits())
+	}
+
+	layoutRegistry.mu.Lock()
+	defer layoutRegistry.mu.Unlock()
+
+	if prev, ok := layoutRegistry.layouts[l.Version]; ok && prev != l {
+		return fmt.Errorf("version %d already registered with a different layout", l.Version)
+	}
+	layoutRegistry.layouts[l.Version] = l
+	return nil
+}
+
+// layoutFor returns the layout to decode id with when no layout is given: the
+// registered layout named by id's version tag, or DefaultLayout.
+func layoutFor(id ID) Layout {
+	l := DefaultLayout()
+	if l.versionbits() == 0 {
+		return l
+	}
+
+	version := uint8((id.Uint64() >> l.versionShift()) & l.versionMask()) //nolint:gosec
+
+	layoutRegistry.mu.RLock()
+	defer layoutRegistry.mu.RUnlock()
+
+	if reg, ok := layoutRegistry.layouts[version]; ok {
+		return reg
+	}
+	return l
+}
@@ -0,0 +1,84 @@
+package crystal
+
+import (
+	"testing"
+	"time"
+)
+
+// resetLayoutRegistry restores the package-level layout knobs and empties the
+// version registry when the test ends.
+func resetLayoutRegistry(t *testing.T) {
+	t.Helper()
+	origTimebits, origVersionbits, origVersion := Timebits, Versionbits, LayoutVersion
+	t.Cleanup(func() {
+		Timebits, Versionbits, LayoutVersion = origTimebits, origVersionbits, origVersion
+
+		layoutRegistry.mu.Lock()
+		defer layoutRegistry.mu.Unlock()
+		clear(layoutRegistry.layouts)
+	})
+}
+
+func TestLayoutVersions(t *testing.T) {
+	resetLayoutRegistry(t)
+
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := newManualClock(start)
+
+	// Version A: 42 time bits.
+	Versionbits, LayoutVersion, Timebits = 2, 0, 42
+	genA := New(WithClock(clock))
+	idA := genA.Generate()
+	if got := genA.Layout().stepBits(); got != 19 {
+		t.Fatalf("version A step bits = %d, want 19", got)
+	}
+
+	// Version B: 44 time bits. The package-level layout now differs from the
+	// one idA was issued under.
+	clock.Add(time.Hour)
+	Versionbits, LayoutVersion, Timebits = 2, 1, 44
+	genB := New(WithClock(clock))
+	idB := genB.Generate()
+
+	if !idA.Time().Equal(start) {
+		t.Errorf("version A ID time = %v, want %v", idA.Time(), start)
+	}
+	if !idB.Time().Equal(start.Add(time.Hour)) {
+		t.Errorf("version B ID time = %v, want %v", idB.Time(), start.Add(time.Hour))
+	}
+	if idB <= idA {
+		t.Errorf("version B ID %d does not sort above version A ID %d", idB, idA)
+	}
+
+	// Decoding with the current package layout alone misreads the old ID.
+	current := DefaultLayout()
+	if current.Time(idA).Equal(start) {
+		t.Error("expected the version B layout to misread the version A ID")
+	}
+}
+
+func TestRegisterLayoutErrors(t *testing.T) {
+	resetLayoutRegistry(t)
+
+	if err := RegisterLayout(DefaultLayout()); err == nil {
+		t.Error("expected error for layout without version bits")
+	}
+
+	l := DefaultLayout()
+	l.Versionbits, l.Version = 1, 2
+	if err := RegisterLayout(l); err == nil {
+		t.Error("expected error for version exceeding its bits")
+	}
+
+	l.Version = 1
+	if err := RegisterLayout(l); err != nil {
+		t.Fatalf("RegisterLayout() failed: %v", err)
+	}
+	if err := RegisterLayout(l); err != nil {
+		t.Errorf("re-registering the same layout failed: %v", err)
+	}
+	l.Timebits = 44
+	if err := RegisterLayout(l); err == nil {
+		t.Error("expected error for conflicting layout with the same version")
+	}
+}